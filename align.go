@@ -0,0 +1,159 @@
+package main
+
+// Выравнивание последовательностей слов. Используется для синхронизации
+// субтитров по аудио, принудительного выравнивания текста и сравнения версий.
+
+// maxDPCells ограничивает размер таблицы LCS; большие участки сначала
+// режутся якорями — словами, которые встречаются ровно один раз в обеих
+// последовательностях (как в patience diff)
+const maxDPCells = 4_000_000
+
+// alignWords сопоставляет слова a и b (уже нормализованные) и возвращает для
+// каждого слова a индекс совпавшего слова b или -1. Совпадения монотонны.
+func alignWords(a, b []string) []int {
+	match := make([]int, len(a))
+	for i := range match {
+		match[i] = -1
+	}
+	alignRange(a, b, 0, len(a), 0, len(b), match)
+	return match
+}
+
+func alignRange(a, b []string, aLo, aHi, bLo, bHi int, match []int) {
+	// Общие префикс и суффикс сопоставляются сразу
+	for aLo < aHi && bLo < bHi && a[aLo] == b[bLo] {
+		match[aLo] = bLo
+		aLo++
+		bLo++
+	}
+	for aLo < aHi && bLo < bHi && a[aHi-1] == b[bHi-1] {
+		match[aHi-1] = bHi - 1
+		aHi--
+		bHi--
+	}
+	if aLo >= aHi || bLo >= bHi {
+		return
+	}
+	if (aHi-aLo)*(bHi-bLo) <= maxDPCells {
+		alignLCS(a, b, aLo, aHi, bLo, bHi, match)
+		return
+	}
+
+	anchors := uniqueAnchors(a, b, aLo, aHi, bLo, bHi)
+	if len(anchors) == 0 {
+		// Якорей нет: выравниваем по диагонали кусками допустимого размера
+		alignDiagonal(a, b, aLo, aHi, bLo, bHi, match)
+		return
+	}
+	prevA, prevB := aLo, bLo
+	for _, an := range anchors {
+		alignRange(a, b, prevA, an[0], prevB, an[1], match)
+		match[an[0]] = an[1]
+		prevA, prevB = an[0]+1, an[1]+1
+	}
+	alignRange(a, b, prevA, aHi, prevB, bHi, match)
+}
+
+// uniqueAnchors находит слова, уникальные в обоих диапазонах, и оставляет
+// из них наибольшую возрастающую цепочку пар (индекс в a, индекс в b)
+func uniqueAnchors(a, b []string, aLo, aHi, bLo, bHi int) [][2]int {
+	type pos struct{ count, idx int }
+	inA := map[string]*pos{}
+	for i := aLo; i < aHi; i++ {
+		if p, ok := inA[a[i]]; ok {
+			p.count++
+		} else {
+			inA[a[i]] = &pos{1, i}
+		}
+	}
+	inB := map[string]*pos{}
+	for j := bLo; j < bHi; j++ {
+		if p, ok := inB[b[j]]; ok {
+			p.count++
+		} else {
+			inB[b[j]] = &pos{1, j}
+		}
+	}
+	var pairs [][2]int
+	for i := aLo; i < aHi; i++ {
+		pa := inA[a[i]]
+		if pb, ok := inB[a[i]]; ok && pa.count == 1 && pb.count == 1 {
+			pairs = append(pairs, [2]int{i, pb.idx})
+		}
+	}
+	return longestIncreasing(pairs)
+}
+
+// longestIncreasing возвращает наибольшую подпоследовательность пар,
+// возрастающую по второму элементу (пары уже упорядочены по первому)
+func longestIncreasing(pairs [][2]int) [][2]int {
+	if len(pairs) == 0 {
+		return nil
+	}
+	tails := []int{}
+	prev := make([]int, len(pairs))
+	for i, p := range pairs {
+		lo, hi := 0, len(tails)
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if pairs[tails[mid]][1] < p[1] {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+		if lo > 0 {
+			prev[i] = tails[lo-1]
+		} else {
+			prev[i] = -1
+		}
+		if lo == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[lo] = i
+		}
+	}
+	result := make([][2]int, len(tails))
+	for i, k := len(tails)-1, tails[len(tails)-1]; i >= 0; i, k = i-1, prev[k] {
+		result[i] = pairs[k]
+	}
+	return result
+}
+
+// alignLCS классическое выравнивание по наибольшей общей подпоследовательности
+func alignLCS(a, b []string, aLo, aHi, bLo, bHi int, match []int) {
+	n, m := aHi-aLo, bHi-bLo
+	dp := make([]int32, (n+1)*(m+1))
+	at := func(i, j int) *int32 { return &dp[i*(m+1)+j] }
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[aLo+i] == b[bLo+j] {
+				*at(i, j) = *at(i+1, j+1) + 1
+			} else {
+				*at(i, j) = max(*at(i+1, j), *at(i, j+1))
+			}
+		}
+	}
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case a[aLo+i] == b[bLo+j]:
+			match[aLo+i] = bLo + j
+			i++
+			j++
+		case *at(i+1, j) >= *at(i, j+1):
+			i++
+		default:
+			j++
+		}
+	}
+}
+
+// alignDiagonal режет оба диапазона на пропорциональные куски и выравнивает
+// каждый отдельно; используется, только если нет ни одного якоря
+func alignDiagonal(a, b []string, aLo, aHi, bLo, bHi int, match []int) {
+	n, m := aHi-aLo, bHi-bLo
+	parts := (n*m)/maxDPCells + 1
+	for p := 0; p < parts; p++ {
+		alignLCS(a, b, aLo+n*p/parts, aLo+n*(p+1)/parts, bLo+m*p/parts, bLo+m*(p+1)/parts, match)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAlignWords(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []int
+	}{
+		{"identical", "a b c", "a b c", []int{0, 1, 2}},
+		{"empty a", "", "a b", []int{}},
+		{"empty b", "a b", "", []int{-1, -1}},
+		{"deletion", "a b c d", "a c d", []int{0, -1, 1, 2}},
+		{"insertion", "a c", "a b c", []int{0, 2}},
+		{"substitution", "a b c", "a x c", []int{0, -1, 2}},
+		{"repeated words", "the cat the dog", "the dog", []int{0, -1, -1, 1}},
+		{"no common words", "a b", "c d", []int{-1, -1}},
+		{"reordered", "a b c", "c b a", []int{-1, -1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := alignWords(strings.Fields(tt.a), strings.Fields(tt.b))
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("alignWords(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+// На длинных последовательностях таблица LCS не строится целиком: диапазон
+// режется якорями. Совпадения должны остаться монотонными и верными.
+func TestAlignWordsLarge(t *testing.T) {
+	const n = 3000
+	var a, b []string
+	for i := 0; i < n; i++ {
+		w := fmt.Sprintf("w%d", i)
+		a = append(a, w)
+		if i%10 == 5 {
+			continue // слово пропущено в b
+		}
+		b = append(b, w)
+		if i%7 == 0 {
+			b = append(b, "extra") // вставка в b
+		}
+	}
+	if len(a)*len(b) <= maxDPCells {
+		t.Fatalf("test input too small to exercise anchors: %d cells", len(a)*len(b))
+	}
+	match := alignWords(a, b)
+	matched, last := 0, -1
+	for i, j := range match {
+		if j < 0 {
+			if i%10 != 5 {
+				t.Fatalf("word %d (%s) left unmatched", i, a[i])
+			}
+			continue
+		}
+		if j <= last {
+			t.Fatalf("match is not monotonic at word %d: %d after %d", i, j, last)
+		}
+		if a[i] != b[j] {
+			t.Fatalf("word %d (%s) matched to %s", i, a[i], b[j])
+		}
+		last = j
+		matched++
+	}
+	if want := n - n/10; matched != want {
+		t.Errorf("matched %d words, want %d", matched, want)
+	}
+}
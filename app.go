@@ -9,10 +9,10 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"os/exec"
 	"os/user"
 	"path/filepath"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
@@ -22,6 +22,24 @@ import (
 type App struct {
 	ctx       context.Context
 	modelsDir string
+
+	// Открытые сессии редактирования субтитров (см. editor.go)
+	sessionsMu sync.Mutex
+	sessions   map[string]*editSession
+	sessionSeq int
+
+	// Отменяемые долгие задачи по ключу (см. jobs.go)
+	jobsMu sync.Mutex
+	jobs   map[string]context.CancelFunc
+
+	// Журнал запусков распознавания (см. history.go)
+	historyMu sync.Mutex
+
+	// Кэш вывода whisper-cli (см. cache.go)
+	cache transcriptionCache
+
+	// Сериализует чтение-изменение-запись settings.json (см. updateSettings)
+	settingsMu sync.Mutex
 }
 
 // NewApp creates a new App application struct
@@ -33,6 +51,23 @@ func NewApp() *App {
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	a.initModelsDir()
+}
+
+// domReady вызывается, когда фронтенд загружен и может принимать события.
+// Прерванные задачи распознавания предлагаются к продолжению событием
+// "incompleteJobs" (см. ResumeJob).
+func (a *App) domReady(ctx context.Context) {
+	jobs, err := a.ListIncompleteJobs()
+	if err != nil || len(jobs) == 0 {
+		return
+	}
+	log.Printf("[domReady] Прерванных задач распознавания: %d\n", len(jobs))
+	runtime.EventsEmit(ctx, "incompleteJobs", jobs)
+}
+
+// initModelsDir определяет каталог моделей; вызывается и без окна (в CLI)
+func (a *App) initModelsDir() {
 	// Определяем каталог для хранения моделей в каталоге пользователя
 	if usr, err := user.Current(); err == nil {
 		a.modelsDir = filepath.Join(usr.HomeDir, ".submagic", "models")
@@ -334,6 +369,16 @@ func (a *App) DeleteModel(name string) error {
 // Settings структура для хранения настроек
 type Settings struct {
 	ActiveModel string `json:"activeModel"`
+	// HallucinationPhrases пользовательские фразы-галлюцинации по языкам
+	HallucinationPhrases map[string][]string `json:"hallucinationPhrases,omitempty"`
+	// RecentProjects пути последних открытых проектов, самый свежий первым
+	RecentProjects []string `json:"recentProjects,omitempty"`
+	// Output куда и под каким именем сохраняются субтитры
+	Output OutputSettings `json:"output"`
+	// CaptionTemplates пользовательские шаблоны анимированных субтитров
+	CaptionTemplates []CaptionTemplate `json:"captionTemplates,omitempty"`
+	// Cache предел размера кэша распознавания
+	Cache CacheSettings `json:"cache"`
 }
 
 // getSettingsPath возвращает путь к файлу настроек
@@ -362,7 +407,8 @@ func (a *App) loadSettings() (*Settings, error) {
 	return &settings, nil
 }
 
-// saveSettings сохраняет настройки в файл
+// saveSettings сохраняет настройки в файл. Запись атомарная, чтобы
+// loadSettings не прочитал недописанный файл.
 func (a *App) saveSettings(settings *Settings) error {
 	settingsPath := a.getSettingsPath()
 	data, err := json.MarshalIndent(settings, "", "  ")
@@ -370,7 +416,23 @@ func (a *App) saveSettings(settings *Settings) error {
 		return err
 	}
 
-	return os.WriteFile(settingsPath, data, 0644)
+	return writeFileAtomic(settingsPath, data)
+}
+
+// updateSettings загружает настройки, изменяет их fn и сохраняет. Вызовы
+// сериализуются, чтобы одновременные изменения разных полей не терялись.
+// Если fn возвращает ошибку, настройки не сохраняются.
+func (a *App) updateSettings(fn func(settings *Settings) error) error {
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+	settings, err := a.loadSettings()
+	if err != nil {
+		return err
+	}
+	if err := fn(settings); err != nil {
+		return err
+	}
+	return a.saveSettings(settings)
 }
 
 // SetActiveModel устанавливает активную модель
@@ -381,24 +443,19 @@ func (a *App) SetActiveModel(name string) error {
 		return errors.New("unknown model")
 	}
 
-	// Загружаем текущие настройки
-	settings, err := a.loadSettings()
-	if err != nil {
-		log.Printf("[SetActiveModel] Ошибка загрузки настроек: %v\n", err)
-		return err
-	}
-
-	// Обновляем активную модель
-	settings.ActiveModel = name
-
-	// Сохраняем настройки
-	err = a.saveSettings(settings)
+	// Обновляем активную модель в настройках
+	err := a.updateSettings(func(settings *Settings) error {
+		settings.ActiveModel = name
+		return nil
+	})
 	if err != nil {
 		log.Printf("[SetActiveModel] Ошибка сохранения настроек: %v\n", err)
 		return err
 	}
 
 	log.Printf("[SetActiveModel] Активная модель установлена: %s\n", name)
+	// Модель всё равно выбирается, но пользователь видит предупреждение
+	a.checkModelMemory(name)
 	return nil
 }
 
@@ -414,88 +471,106 @@ func (a *App) GetActiveModel() (string, error) {
 	return settings.ActiveModel, nil
 }
 
-func (a *App) GenerateSubtitles(ctx context.Context, filePath string, lang string, modelName string) (string, error) {
-	log.Printf("[GenerateSubtitles] Генерация субтитров: файл=%s, язык=%s, модель=%s\n", filePath, lang, modelName)
+const whisperPath = "./whisper-cli"
+
+// resolveModelPath возвращает путь к скачанному файлу модели
+func (a *App) resolveModelPath(modelName string) (string, error) {
 	info, ok := whisperModels[modelName]
 	if !ok {
-		log.Printf("[GenerateSubtitles] Неизвестная модель: %s\n", modelName)
 		return "", errors.New("unknown model")
 	}
 	modelPath := filepath.Join(a.modelsDir, filepath.Base(info.URL))
 	if _, err := os.Stat(modelPath); err != nil {
-		log.Printf("[GenerateSubtitles] Модель не найдена: %s\n", modelPath)
 		return "", errors.New("Модель не найдена. Скачайте её в настройках.")
 	}
-	whisperPath := "./whisper-cli"
-	outSRT := filePath + ".srt"
-	_ = os.Remove(outSRT)
+	return modelPath, nil
+}
 
+// GenerateSubtitles транскрибирует файл и сохраняет SRT по настройкам вывода.
+// forceRefresh распознаёт заново, не используя кэш (см. cache.go).
+func (a *App) GenerateSubtitles(ctx context.Context, filePath string, lang string, modelName string, forceRefresh bool) (_ string, err error) {
+	log.Printf("[GenerateSubtitles] Генерация субтитров: файл=%s, язык=%s, модель=%s\n", filePath, lang, modelName)
 	if lang == "" {
 		lang = "ru"
 	}
+	opts := TranscribeOptions{Lang: lang, Model: modelName, ForceRefresh: forceRefresh}
+	run := a.startHistory("full", filePath, opts)
+	defer func() { a.finishHistory(run, err) }()
 
-	cmd := exec.Command(whisperPath, "-m", modelPath, "-f", filePath, "-otxt", "-osrt", "-l", lang)
-	err := cmd.Run()
+	modelPath, err := a.resolveModelPath(modelName)
 	if err != nil {
-		log.Printf("[GenerateSubtitles] Ошибка запуска whisper-cli: %v\n", err)
+		log.Printf("[GenerateSubtitles] %v\n", err)
 		return "", err
 	}
 
-	srtData, err := os.ReadFile(outSRT)
-	if err != nil {
-		log.Printf("[GenerateSubtitles] Ошибка чтения SRT: %v\n", err)
-		return "", err
+	var cues []SubtitleCue
+	if duration, derr := probeMediaDuration(filePath); derr == nil && duration > checkpointThreshold {
+		// Длинный файл распознаётся кусками с чекпоинтами: после сбоя
+		// задачу можно продолжить через ResumeJob
+		job, err := a.newTranscriptionJob(filePath, opts)
+		if err != nil {
+			log.Printf("[GenerateSubtitles] Ошибка планирования задачи: %v\n", err)
+			return "", err
+		}
+		if cues, err = a.runTranscriptionJob(job, modelPath); err != nil {
+			log.Printf("[GenerateSubtitles] Задача %s прервана: %v\n", job.ID, err)
+			return "", err
+		}
+	} else {
+		// whisper-cli пишет результат во временный каталог, рядом с видео
+		// сохраняется только итоговый файл по шаблону из настроек вывода
+		out, err := a.runWhisper(filePath, modelPath, 0, 0, opts)
+		if err != nil {
+			log.Printf("[GenerateSubtitles] Ошибка запуска whisper-cli: %v\n", err)
+			return "", err
+		}
+		cues = out.cues(false, false)
 	}
+	doc := SubtitleDocument{Language: lang, Model: modelName, Cues: cues}
+	srt := a.saveGeneratedSubtitles(filePath, &doc, run)
 	log.Printf("[GenerateSubtitles] Субтитры успешно сгенерированы для файла: %s\n", filePath)
-	return string(srtData), nil
+	return srt, nil
 }
 
-// GenerateSubtitlesChunk генерирует субтитры для куска видео (startSec-endSec)
-func (a *App) GenerateSubtitlesChunk(ctx context.Context, filePath string, lang string, modelName string, startSec, endSec int) (string, error) {
-	log.Printf("[GenerateSubtitlesChunk] Генерация субтитров: файл=%s, язык=%s, модель=%s, start=%d, end=%d\n", filePath, lang, modelName, startSec, endSec)
-	info, ok := whisperModels[modelName]
-	if !ok {
-		log.Printf("[GenerateSubtitlesChunk] Неизвестная модель: %s\n", modelName)
-		return "", errors.New("unknown model")
-	}
-	modelPath := filepath.Join(a.modelsDir, filepath.Base(info.URL))
-	if _, err := os.Stat(modelPath); err != nil {
-		log.Printf("[GenerateSubtitlesChunk] Модель не найдена: %s\n", modelPath)
-		return "", errors.New("Модель не найдена. Скачайте её в настройках.")
+// saveGeneratedSubtitles сохраняет SRT по настройкам вывода и возвращает его
+// текст. Ошибка сохранения файла только логируется: текст всё равно уходит
+// во фронтенд.
+func (a *App) saveGeneratedSubtitles(filePath string, doc *SubtitleDocument, run *HistoryEntry) string {
+	srt := formatSRT(doc.Cues)
+	run.Cues = len(doc.Cues)
+	if outPath, err := a.writeSubtitleFile(filePath, doc, "srt", []byte(srt)); err != nil {
+		log.Printf("[saveGeneratedSubtitles] Файл субтитров не сохранён: %v\n", err)
+	} else {
+		log.Printf("[saveGeneratedSubtitles] Субтитры сохранены: %s\n", outPath)
+		run.Outputs = append(run.Outputs, outPath)
 	}
+	return srt
+}
+
+// GenerateSubtitlesChunk генерирует субтитры для куска видео (startSec-endSec);
+// forceRefresh — как в GenerateSubtitles
+func (a *App) GenerateSubtitlesChunk(ctx context.Context, filePath string, lang string, modelName string, startSec, endSec int, forceRefresh bool) (_ string, err error) {
+	log.Printf("[GenerateSubtitlesChunk] Генерация субтитров: файл=%s, язык=%s, модель=%s, start=%d, end=%d\n", filePath, lang, modelName, startSec, endSec)
 	if lang == "" {
 		lang = "ru"
 	}
-	// Нарезаем кусок видео через ffmpeg
-	tmpDir := os.TempDir()
-	tmpChunk := filepath.Join(tmpDir, fmt.Sprintf("submagic_chunk_%d_%d.mp4", startSec, endSec))
-	_ = os.Remove(tmpChunk)
-	dur := endSec - startSec
-	ffmpegPath := "ffmpeg"
-	ffArgs := []string{"-y", "-i", filePath, "-ss", fmt.Sprintf("%d", startSec), "-t", fmt.Sprintf("%d", dur), "-c:v", "copy", "-c:a", "copy", tmpChunk}
-	cmdFfmpeg := exec.Command(ffmpegPath, ffArgs...)
-	ffmpegOut, err := cmdFfmpeg.CombinedOutput()
-	if err != nil {
-		log.Printf("[GenerateSubtitlesChunk] ffmpeg error: %v, out: %s\n", err, string(ffmpegOut))
-		return "", fmt.Errorf("ffmpeg error: %v, out: %s", err, string(ffmpegOut))
-	}
-	// Генерируем субтитры для куска
-	whisperPath := "./whisper-cli"
-	outSRT := tmpChunk + ".srt"
-	_ = os.Remove(outSRT)
-	cmd := exec.Command(whisperPath, "-m", modelPath, "-f", tmpChunk, "-otxt", "-osrt", "-l", lang)
-	err = cmd.Run()
+	opts := TranscribeOptions{Lang: lang, Model: modelName, ForceRefresh: forceRefresh}
+	run := a.startHistory("chunk", filePath, opts)
+	run.Start, run.End = startSec, endSec
+	defer func() { a.finishHistory(run, err) }()
+
+	modelPath, err := a.resolveModelPath(modelName)
 	if err != nil {
-		log.Printf("[GenerateSubtitlesChunk] Ошибка запуска whisper-cli: %v\n", err)
+		log.Printf("[GenerateSubtitlesChunk] %v\n", err)
 		return "", err
 	}
-	srtData, err := os.ReadFile(outSRT)
+	// Кусок вырезается в WAV через ffmpeg, время реплик — относительно startSec
+	cues, err := a.transcribeSegment(filePath, modelPath, float64(startSec), float64(endSec), opts)
 	if err != nil {
-		log.Printf("[GenerateSubtitlesChunk] Ошибка чтения SRT: %v\n", err)
+		log.Printf("[GenerateSubtitlesChunk] Ошибка транскрибации куска: %v\n", err)
 		return "", err
 	}
-	_ = os.Remove(tmpChunk)
-	_ = os.Remove(outSRT)
 	log.Printf("[GenerateSubtitlesChunk] Субтитры успешно сгенерированы для куска: %d-%d\n", startSec, endSec)
-	return string(srtData), nil
+	run.Cues = len(cues)
+	return formatSRT(cues), nil
 }
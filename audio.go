@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+const (
+	ffmpegPath  = "ffmpeg"
+	ffprobePath = "ffprobe"
+)
+
+// SilenceInterval участок тишины в секундах от начала файла
+type SilenceInterval struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// probeMediaDuration возвращает длительность медиафайла в секундах через ffprobe
+func probeMediaDuration(filePath string) (float64, error) {
+	cmd := exec.Command(ffprobePath, "-v", "error", "-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1", filePath)
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe error: %v", err)
+	}
+	duration, err := strconv.ParseFloat(strings.TrimSpace(string(out)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe: cannot parse duration %q", strings.TrimSpace(string(out)))
+	}
+	return duration, nil
+}
+
+// extractAudioSegment вырезает кусок аудио [start, end) в WAV 16 кГц моно,
+// который понимает whisper-cli. end <= 0 означает «до конца файла».
+func extractAudioSegment(filePath, outPath string, start, end float64) error {
+	args := []string{"-y", "-i", filePath}
+	if start > 0 {
+		args = append(args, "-ss", strconv.FormatFloat(start, 'f', 3, 64))
+	}
+	if end > 0 {
+		args = append(args, "-to", strconv.FormatFloat(end, 'f', 3, 64))
+	}
+	args = append(args, "-vn", "-acodec", "pcm_s16le", "-ar", "16000", "-ac", "1", outPath)
+	out, err := exec.Command(ffmpegPath, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ffmpeg error: %v, out: %s", err, string(out))
+	}
+	return nil
+}
+
+// detectSilences находит паузы фильтром silencedetect.
+// noiseDb — порог тишины (например -35), minDuration — минимальная длина паузы в секундах.
+func detectSilences(filePath string, noiseDb, minDuration float64) ([]SilenceInterval, error) {
+	filter := fmt.Sprintf("silencedetect=noise=%.1fdB:d=%.2f", noiseDb, minDuration)
+	cmd := exec.Command(ffmpegPath, "-hide_banner", "-nostats", "-i", filePath, "-vn", "-af", filter, "-f", "null", "-")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		log.Printf("[detectSilences] ffmpeg error: %v\n", err)
+		return nil, fmt.Errorf("ffmpeg error: %v, out: %s", err, string(out))
+	}
+	return parseSilenceDetect(out), nil
+}
+
+// parseSilenceDetect разбирает вывод silencedetect:
+//
+//	[silencedetect @ 0x...] silence_start: 12.34
+//	[silencedetect @ 0x...] silence_end: 13.5 | silence_duration: 1.16
+//
+// Незакрытая пауза в конце файла получает End = -1.
+func parseSilenceDetect(out []byte) []SilenceInterval {
+	var result []SilenceInterval
+	open := false
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "silence_start:"); i >= 0 {
+			v, err := strconv.ParseFloat(strings.TrimSpace(line[i+len("silence_start:"):]), 64)
+			if err != nil {
+				continue
+			}
+			if v < 0 {
+				v = 0
+			}
+			result = append(result, SilenceInterval{Start: v, End: -1})
+			open = true
+		} else if i := strings.Index(line, "silence_end:"); i >= 0 && open {
+			fields := strings.Fields(line[i+len("silence_end:"):])
+			if len(fields) == 0 {
+				continue
+			}
+			v, err := strconv.ParseFloat(fields[0], 64)
+			if err != nil {
+				continue
+			}
+			result[len(result)-1].End = v
+			open = false
+		}
+	}
+	return result
+}
@@ -0,0 +1,469 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// TextNormalization правила приведения текста перед подсчётом ошибок
+type TextNormalization struct {
+	Lowercase        bool `json:"lowercase"`
+	StripPunctuation bool `json:"stripPunctuation"`
+	FoldYo           bool `json:"foldYo"` // ё → е: в русских эталонах «ё» ставят непоследовательно
+}
+
+// defaultTextNormalization включает все правила
+var defaultTextNormalization = TextNormalization{Lowercase: true, StripPunctuation: true, FoldYo: true}
+
+// BenchmarkItem пара «медиафайл + эталонная расшифровка» (TXT или файл субтитров)
+type BenchmarkItem struct {
+	Media     string `json:"media"`
+	Reference string `json:"reference"`
+}
+
+// BenchmarkConfig параметры прогона. Корпус задаётся списком Items или
+// каталогом CorpusDir, где у каждого медиафайла рядом лежит эталон с тем же
+// именем (.txt, .srt, .vtt, .ass).
+type BenchmarkConfig struct {
+	Models        []string          `json:"models"`
+	Items         []BenchmarkItem   `json:"items,omitempty"`
+	CorpusDir     string            `json:"corpusDir,omitempty"`
+	Lang          string            `json:"lang"`
+	Normalization TextNormalization `json:"normalization"`
+	// ReportPath путь отчёта без расширения; Formats — json, csv, md
+	ReportPath string   `json:"reportPath,omitempty"`
+	Formats    []string `json:"formats,omitempty"`
+}
+
+// BenchmarkResult результат одной модели на одном файле
+type BenchmarkResult struct {
+	Model         string  `json:"model"`
+	Media         string  `json:"media"`
+	RefWords      int     `json:"refWords"`
+	Substitutions int     `json:"substitutions"`
+	Deletions     int     `json:"deletions"`
+	Insertions    int     `json:"insertions"`
+	WER           float64 `json:"wer"`
+	RefChars      int     `json:"refChars"`
+	CharErrors    int     `json:"charErrors"`
+	CER           float64 `json:"cer"`
+	Duration      float64 `json:"duration"`             // длительность медиа, с
+	Elapsed       float64 `json:"elapsed"`              // время распознавания, с
+	RTF           float64 `json:"rtf"`                  // elapsed / duration, меньше — быстрее
+	PeakMemory    int64   `json:"peakMemory,omitempty"` // 0 — неизвестен
+	Error         string  `json:"error,omitempty"`
+}
+
+// BenchmarkModelSummary сводка по модели на всём корпусе. WER и CER
+// считаются по сумме ошибок, а не как среднее по файлам.
+type BenchmarkModelSummary struct {
+	Model      string  `json:"model"`
+	Files      int     `json:"files"`
+	Failed     int     `json:"failed"`
+	WER        float64 `json:"wer"`
+	CER        float64 `json:"cer"`
+	RTF        float64 `json:"rtf"`
+	PeakMemory int64   `json:"peakMemory,omitempty"` // 0 — неизвестен
+}
+
+// BenchmarkReport отчёт прогона
+type BenchmarkReport struct {
+	Started       time.Time               `json:"started"`
+	Lang          string                  `json:"lang"`
+	Normalization TextNormalization       `json:"normalization"`
+	Summary       []BenchmarkModelSummary `json:"summary"`
+	Results       []BenchmarkResult       `json:"results"`
+	ReportFiles   []string                `json:"reportFiles,omitempty"`
+}
+
+// normalizeForScoring разбивает текст на слова по правилам n
+func normalizeForScoring(text string, n TextNormalization) []string {
+	if n.Lowercase {
+		text = strings.ToLower(text)
+	}
+	if n.FoldYo {
+		text = strings.NewReplacer("ё", "е", "Ё", "Е").Replace(text)
+	}
+	var words []string
+	for _, f := range strings.Fields(text) {
+		if n.StripPunctuation {
+			f = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(".,!?…:;\"'()[]«»„“”—–-", r) {
+					return -1
+				}
+				return r
+			}, f)
+		}
+		if f != "" {
+			words = append(words, f)
+		}
+	}
+	return words
+}
+
+// levenshtein считает замены, удаления и вставки, превращающие ref в hyp
+func levenshtein[T comparable](ref, hyp []T) (sub, del, ins int) {
+	type cell struct{ cost, sub, del, ins int }
+	prev := make([]cell, len(hyp)+1)
+	cur := make([]cell, len(hyp)+1)
+	for j := range prev {
+		prev[j] = cell{cost: j, ins: j}
+	}
+	for i := 1; i <= len(ref); i++ {
+		cur[0] = cell{cost: i, del: i}
+		for j := 1; j <= len(hyp); j++ {
+			if ref[i-1] == hyp[j-1] {
+				cur[j] = prev[j-1]
+				continue
+			}
+			best := prev[j-1]
+			best.cost++
+			best.sub++
+			if c := prev[j]; c.cost+1 < best.cost {
+				best = c
+				best.cost++
+				best.del++
+			}
+			if c := cur[j-1]; c.cost+1 < best.cost {
+				best = c
+				best.cost++
+				best.ins++
+			}
+			cur[j] = best
+		}
+		prev, cur = cur, prev
+	}
+	last := prev[len(hyp)]
+	return last.sub, last.del, last.ins
+}
+
+// scoreTranscript считает ошибки на уровне слов и символов. Если таблица
+// Левенштейна не больше maxDPCells, расстояние считается точно; на часовых
+// записях совпавшие слова сначала сопоставляются alignWords, а расстояние
+// считается только в промежутках между ними.
+func scoreTranscript(ref, hyp []string, r *BenchmarkResult) {
+	refText, hypText := []rune(strings.Join(ref, " ")), []rune(strings.Join(hyp, " "))
+	r.RefWords = len(ref)
+	r.RefChars = len(refText)
+	exactWords := len(ref)*len(hyp) <= maxDPCells
+	exactChars := len(refText)*len(hypText) <= maxDPCells
+	if exactWords {
+		r.Substitutions, r.Deletions, r.Insertions = levenshtein(ref, hyp)
+	}
+	if exactChars {
+		cs, cd, ci := levenshtein(refText, hypText)
+		r.CharErrors = cs + cd + ci
+	}
+	if !exactWords || !exactChars {
+		// Каждое слово промежутка берётся с пробелом после него: удаление
+		// или вставка слова добавляет и разделитель
+		withSpaces := func(words []string) []rune {
+			var b strings.Builder
+			for _, w := range words {
+				b.WriteString(w + " ")
+			}
+			return []rune(b.String())
+		}
+		gap := func(i0, i1, j0, j1 int) {
+			if !exactWords {
+				s, d, in := levenshtein(ref[i0:i1], hyp[j0:j1])
+				r.Substitutions += s
+				r.Deletions += d
+				r.Insertions += in
+			}
+			if !exactChars {
+				cs, cd, ci := levenshtein(withSpaces(ref[i0:i1]), withSpaces(hyp[j0:j1]))
+				r.CharErrors += cs + cd + ci
+			}
+		}
+		i0, j0 := 0, 0
+		for i, j := range alignWords(ref, hyp) {
+			if j < 0 {
+				continue
+			}
+			gap(i0, i, j0, j)
+			i0, j0 = i+1, j+1
+		}
+		gap(i0, len(ref), j0, len(hyp))
+	}
+	if r.RefWords > 0 {
+		r.WER = float64(r.Substitutions+r.Deletions+r.Insertions) / float64(r.RefWords)
+	}
+	if r.RefChars > 0 {
+		r.CER = float64(r.CharErrors) / float64(r.RefChars)
+	}
+}
+
+// benchmarkMediaExts расширения медиафайлов, которые ищутся в CorpusDir
+var benchmarkMediaExts = []string{".wav", ".mp3", ".m4a", ".flac", ".ogg", ".opus", ".mp4", ".mkv", ".mov", ".webm"}
+
+// loadBenchmarkCorpus находит пары «медиа + эталон» в каталоге
+func loadBenchmarkCorpus(dir string) ([]BenchmarkItem, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var items []BenchmarkItem
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		isMedia := false
+		for _, m := range benchmarkMediaExts {
+			isMedia = isMedia || ext == m
+		}
+		if e.IsDir() || !isMedia {
+			continue
+		}
+		base := filepath.Join(dir, strings.TrimSuffix(e.Name(), filepath.Ext(e.Name())))
+		for _, refExt := range []string{".txt", ".srt", ".vtt", ".ass"} {
+			if _, err := os.Stat(base + refExt); err == nil {
+				items = append(items, BenchmarkItem{Media: filepath.Join(dir, e.Name()), Reference: base + refExt})
+				break
+			}
+		}
+	}
+	return items, nil
+}
+
+// readReferenceText читает эталон: TXT как есть, субтитры — текстом реплик
+func (a *App) readReferenceText(path string) (string, error) {
+	if strings.EqualFold(filepath.Ext(path), ".txt") {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		text, _ := decodeSubtitleText(data)
+		return text, nil
+	}
+	imported, err := a.ImportSubtitles(path)
+	if err != nil {
+		return "", err
+	}
+	texts := make([]string, len(imported.Document.Cues))
+	for i, c := range imported.Document.Cues {
+		texts[i] = c.Text
+	}
+	return strings.Join(texts, "\n"), nil
+}
+
+// RunBenchmark прогоняет модели по корпусу и считает WER, CER, RTF и
+// пиковую память whisper-cli. Прогресс отправляется событием
+// "benchmarkProgress"; отчёт пишется в ReportPath, если он задан.
+func (a *App) RunBenchmark(cfg BenchmarkConfig) (*BenchmarkReport, error) {
+	log.Printf("[RunBenchmark] Модели: %v, корпус: %d файлов, каталог: %q\n", cfg.Models, len(cfg.Items), cfg.CorpusDir)
+	items := cfg.Items
+	if cfg.CorpusDir != "" {
+		found, err := loadBenchmarkCorpus(cfg.CorpusDir)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, found...)
+	}
+	if len(items) == 0 {
+		return nil, errors.New("benchmark corpus is empty")
+	}
+	if len(cfg.Models) == 0 {
+		return nil, errors.New("no models selected")
+	}
+	for _, m := range cfg.Models {
+		if _, err := a.resolveModelPath(m); err != nil {
+			return nil, fmt.Errorf("%s: %v", m, err)
+		}
+	}
+
+	refs := make([][]string, len(items))
+	durations := make([]float64, len(items))
+	for i, it := range items {
+		text, err := a.readReferenceText(it.Reference)
+		if err != nil {
+			return nil, fmt.Errorf("reference %s: %v", it.Reference, err)
+		}
+		refs[i] = normalizeForScoring(text, cfg.Normalization)
+		if durations[i], err = probeMediaDuration(it.Media); err != nil {
+			return nil, fmt.Errorf("%s: %v", it.Media, err)
+		}
+	}
+
+	report := &BenchmarkReport{Started: time.Now(), Lang: cfg.Lang, Normalization: cfg.Normalization}
+	total, done := len(cfg.Models)*len(items), 0
+	for _, model := range cfg.Models {
+		// Замер времени и памяти имеет смысл только без кэша, а результаты
+		// замеров не должны вытеснять из кэша записи пользователя
+		opts := TranscribeOptions{Lang: cfg.Lang, Model: model, noCache: true}
+		if err := normalizeTranscribeOptions(&opts); err != nil {
+			return nil, err
+		}
+		modelPath, _ := a.resolveModelPath(model)
+		for i, it := range items {
+			r := BenchmarkResult{Model: model, Media: it.Media, Duration: durations[i]}
+			out, err := a.runWhisper(it.Media, modelPath, 0, 0, opts)
+			if err != nil {
+				log.Printf("[RunBenchmark] %s / %s: %v\n", model, it.Media, err)
+				r.Error = err.Error()
+			} else {
+				var texts []string
+				for _, c := range out.cues(false, false) {
+					texts = append(texts, c.Text)
+				}
+				scoreTranscript(refs[i], normalizeForScoring(strings.Join(texts, " "), cfg.Normalization), &r)
+				// Время — только работы whisper-cli, без извлечения звука
+				r.Elapsed = out.elapsed.Seconds()
+				r.PeakMemory = out.peakMemory
+				if r.Duration > 0 {
+					r.RTF = r.Elapsed / r.Duration
+				}
+			}
+			report.Results = append(report.Results, r)
+			done++
+			log.Printf("[RunBenchmark] %d/%d %s %s: WER=%.3f CER=%.3f RTF=%.2f\n", done, total, model, filepath.Base(it.Media), r.WER, r.CER, r.RTF)
+			if a.ctx != nil {
+				runtime.EventsEmit(a.ctx, "benchmarkProgress", map[string]interface{}{
+					"done":   done,
+					"total":  total,
+					"result": r,
+				})
+			}
+		}
+	}
+	report.Summary = summarizeBenchmark(cfg.Models, report.Results)
+
+	if cfg.ReportPath != "" {
+		files, err := writeBenchmarkReport(report, cfg.ReportPath, cfg.Formats)
+		if err != nil {
+			log.Printf("[RunBenchmark] Ошибка записи отчёта: %v\n", err)
+			return report, err
+		}
+		report.ReportFiles = files
+	}
+	return report, nil
+}
+
+func summarizeBenchmark(models []string, results []BenchmarkResult) []BenchmarkModelSummary {
+	var summary []BenchmarkModelSummary
+	for _, model := range models {
+		s := BenchmarkModelSummary{Model: model}
+		var words, wordErrors, chars, charErrors int
+		var elapsed, duration float64
+		for _, r := range results {
+			if r.Model != model {
+				continue
+			}
+			s.Files++
+			if r.Error != "" {
+				s.Failed++
+				continue
+			}
+			words += r.RefWords
+			wordErrors += r.Substitutions + r.Deletions + r.Insertions
+			chars += r.RefChars
+			charErrors += r.CharErrors
+			elapsed += r.Elapsed
+			duration += r.Duration
+			s.PeakMemory = max(s.PeakMemory, r.PeakMemory)
+		}
+		if words > 0 {
+			s.WER = float64(wordErrors) / float64(words)
+		}
+		if chars > 0 {
+			s.CER = float64(charErrors) / float64(chars)
+		}
+		if duration > 0 {
+			s.RTF = elapsed / duration
+		}
+		summary = append(summary, s)
+	}
+	// Лучшие модели — первыми; модели, не распознавшие ни одного файла,
+	// не оценены и идут в конце
+	sort.SliceStable(summary, func(i, j int) bool {
+		failedI, failedJ := summary[i].Failed == summary[i].Files, summary[j].Failed == summary[j].Files
+		if failedI != failedJ {
+			return failedJ
+		}
+		return summary[i].WER < summary[j].WER
+	})
+	return summary
+}
+
+// writeBenchmarkReport пишет отчёт в форматах formats (по умолчанию все три)
+func writeBenchmarkReport(report *BenchmarkReport, basePath string, formats []string) ([]string, error) {
+	if len(formats) == 0 {
+		formats = []string{"json", "csv", "md"}
+	}
+	var files []string
+	for _, f := range formats {
+		var data []byte
+		var err error
+		switch strings.ToLower(f) {
+		case "json":
+			data, err = json.MarshalIndent(report, "", "  ")
+		case "csv":
+			data, err = benchmarkCSV(report)
+		case "md", "markdown":
+			f = "md"
+			data = []byte(benchmarkMarkdown(report))
+		default:
+			return files, fmt.Errorf("unknown report format %q", f)
+		}
+		if err != nil {
+			return files, err
+		}
+		path := basePath + "." + strings.ToLower(f)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return files, err
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// memoryMB форматирует объём памяти в мегабайтах; неизвестный (0) — как unknown
+func memoryMB(bytes int64, unknown string) string {
+	if bytes <= 0 {
+		return unknown
+	}
+	return strconv.FormatInt(bytes/(1024*1024), 10)
+}
+
+func benchmarkCSV(report *BenchmarkReport) ([]byte, error) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	_ = w.Write([]string{"model", "media", "ref_words", "substitutions", "deletions", "insertions", "wer", "ref_chars", "char_errors", "cer", "duration_s", "elapsed_s", "rtf", "peak_memory_mb", "error"})
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) }
+	for _, r := range report.Results {
+		_ = w.Write([]string{
+			r.Model, r.Media, strconv.Itoa(r.RefWords), strconv.Itoa(r.Substitutions), strconv.Itoa(r.Deletions),
+			strconv.Itoa(r.Insertions), f(r.WER), strconv.Itoa(r.RefChars), strconv.Itoa(r.CharErrors), f(r.CER),
+			f(r.Duration), f(r.Elapsed), f(r.RTF), memoryMB(r.PeakMemory, ""), r.Error,
+		})
+	}
+	w.Flush()
+	return []byte(b.String()), w.Error()
+}
+
+func benchmarkMarkdown(report *BenchmarkReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Сравнение моделей Whisper\n\n%s, язык: %s, файлов: %d\n\n", report.Started.Format("2006-01-02 15:04"), report.Lang, len(report.Results)/max(len(report.Summary), 1))
+	b.WriteString("| Модель | WER | CER | RTF | Пик памяти, МБ | Ошибок запуска |\n|---|---:|---:|---:|---:|---:|\n")
+	for _, s := range report.Summary {
+		fmt.Fprintf(&b, "| %s | %.2f%% | %.2f%% | %.2f | %s | %d |\n", s.Model, s.WER*100, s.CER*100, s.RTF, memoryMB(s.PeakMemory, "—"), s.Failed)
+	}
+	b.WriteString("\n## По файлам\n\n| Модель | Файл | WER | CER | RTF |\n|---|---|---:|---:|---:|\n")
+	for _, r := range report.Results {
+		if r.Error != "" {
+			fmt.Fprintf(&b, "| %s | %s | ошибка: %s | | |\n", r.Model, filepath.Base(r.Media), strings.NewReplacer("|", "/", "\n", " ").Replace(r.Error))
+			continue
+		}
+		fmt.Fprintf(&b, "| %s | %s | %.2f%% | %.2f%% | %.2f |\n", r.Model, filepath.Base(r.Media), r.WER*100, r.CER*100, r.RTF)
+	}
+	return b.String()
+}
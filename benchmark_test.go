@@ -0,0 +1,77 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		ref, hyp      string
+		sub, del, ins int
+	}{
+		{"a b c", "a b c", 0, 0, 0},
+		{"a b c", "a x c", 1, 0, 0},
+		{"a b c", "a c", 0, 1, 0},
+		{"a c", "a b c", 0, 0, 1},
+		{"", "a b", 0, 0, 2},
+		{"a b", "", 0, 2, 0},
+		{"a b c d", "b c d e", 0, 1, 1},
+	}
+	for _, tt := range tests {
+		sub, del, ins := levenshtein(strings.Fields(tt.ref), strings.Fields(tt.hyp))
+		if sub != tt.sub || del != tt.del || ins != tt.ins {
+			t.Errorf("levenshtein(%q, %q) = %d/%d/%d, want %d/%d/%d", tt.ref, tt.hyp, sub, del, ins, tt.sub, tt.del, tt.ins)
+		}
+	}
+}
+
+func TestScoreTranscript(t *testing.T) {
+	tests := []struct {
+		ref, hyp             string
+		sub, del, ins, chars int
+		wer, cer             float64
+	}{
+		{"мама мыла раму", "мама мыла раму", 0, 0, 0, 0, 0, 0},
+		{"мама мыла раму", "мама мыла рану", 1, 0, 0, 1, 1.0 / 3, 1.0 / 14},
+		{"мама мыла раму", "мама раму", 0, 1, 0, 5, 1.0 / 3, 5.0 / 14},
+		{"мама мыла раму", "мама мыла мыла раму", 0, 0, 1, 5, 1.0 / 3, 5.0 / 14},
+		{"a b c d", "b c d e", 0, 1, 1, 4, 2.0 / 4, 4.0 / 7},
+		{"", "", 0, 0, 0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		var r BenchmarkResult
+		scoreTranscript(strings.Fields(tt.ref), strings.Fields(tt.hyp), &r)
+		if r.Substitutions != tt.sub || r.Deletions != tt.del || r.Insertions != tt.ins || r.CharErrors != tt.chars {
+			t.Errorf("scoreTranscript(%q, %q) = S%d D%d I%d C%d, want S%d D%d I%d C%d", tt.ref, tt.hyp,
+				r.Substitutions, r.Deletions, r.Insertions, r.CharErrors, tt.sub, tt.del, tt.ins, tt.chars)
+		}
+		if math.Abs(r.WER-tt.wer) > 1e-9 || math.Abs(r.CER-tt.cer) > 1e-9 {
+			t.Errorf("scoreTranscript(%q, %q) WER %.4f CER %.4f, want %.4f %.4f", tt.ref, tt.hyp, r.WER, r.CER, tt.wer, tt.cer)
+		}
+	}
+}
+
+func TestNormalizeForScoring(t *testing.T) {
+	got := strings.Join(normalizeForScoring("Ёлка, «Ель» — ЕЛЬ!", defaultTextNormalization), " ")
+	if want := "елка ель ель"; got != want {
+		t.Errorf("normalizeForScoring = %q, want %q", got, want)
+	}
+}
+
+func TestSummarizeBenchmarkFailedLast(t *testing.T) {
+	results := []BenchmarkResult{
+		{Model: "broken", Error: "whisper-cli error"},
+		{Model: "good", RefWords: 10, Substitutions: 1},
+		{Model: "worse", RefWords: 10, Substitutions: 3},
+	}
+	summary := summarizeBenchmark([]string{"broken", "worse", "good"}, results)
+	var order []string
+	for _, s := range summary {
+		order = append(order, s.Model)
+	}
+	if got := strings.Join(order, ","); got != "good,worse,broken" {
+		t.Errorf("summary order = %s, want good,worse,broken", got)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// BilingualResult пара выровненных документов: оригинал и английский перевод.
+// Реплики Translation совпадают с репликами Original по номеру и времени.
+type BilingualResult struct {
+	Original    *SubtitleDocument `json:"original"`
+	Translation *SubtitleDocument `json:"translation"`
+}
+
+// TranscribeBilingual параллельно запускает транскрибацию и перевод на английский
+// и выравнивает перевод по репликам оригинала
+func (a *App) TranscribeBilingual(filePath string, opts TranscribeOptions) (*BilingualResult, error) {
+	log.Printf("[TranscribeBilingual] Двуязычные субтитры: файл=%s, опции=%+v\n", filePath, opts)
+	if englishOnlyModel(opts.Model) {
+		return nil, errors.New("Модель " + opts.Model + " знает только английский и не умеет переводить")
+	}
+	// Язык оригинала определяется один раз: иначе перевод определил бы его
+	// автоматически и мог бы разойтись с транскрибацией
+	if opts.Lang == "" {
+		opts.Lang = "ru"
+	}
+	origOpts := opts
+	origOpts.Translate = false
+	trOpts := opts
+	trOpts.Translate = true
+
+	var orig, tr *SubtitleDocument
+	var origErr, trErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		orig, origErr = a.Transcribe(filePath, origOpts)
+	}()
+	go func() {
+		defer wg.Done()
+		tr, trErr = a.Transcribe(filePath, trOpts)
+	}()
+	wg.Wait()
+	if origErr != nil {
+		return nil, origErr
+	}
+	if trErr != nil {
+		return nil, trErr
+	}
+
+	aligned, err := alignTranslation(orig, tr)
+	if err != nil {
+		log.Printf("[TranscribeBilingual] %v\n", err)
+		return nil, err
+	}
+	return &BilingualResult{Original: orig, Translation: aligned}, nil
+}
+
+// alignTranslation переносит текст перевода на реплики оригинала: каждая реплика
+// перевода достаётся той реплике оригинала, с которой сильнее всего пересекается
+// по времени (или ближайшей, если пересечений нет). Если в оригинале нет
+// реплик, перевод привязать не к чему, и это ошибка, а не пустой перевод.
+func alignTranslation(orig, tr *SubtitleDocument) (*SubtitleDocument, error) {
+	if len(orig.Cues) == 0 && len(tr.Cues) > 0 {
+		return nil, errors.New("Оригинал не распознан: перевод не к чему привязать")
+	}
+	texts := make([][]string, len(orig.Cues))
+	for _, t := range tr.Cues {
+		best := -1
+		var bestScore int64
+		for i, o := range orig.Cues {
+			overlap := min(o.End, t.End) - max(o.Start, t.Start)
+			if best == -1 || overlap > bestScore {
+				best = i
+				bestScore = overlap
+			}
+		}
+		if best >= 0 {
+			texts[best] = append(texts[best], t.Text)
+		}
+	}
+
+	aligned := &SubtitleDocument{
+		Language: tr.Language,
+		Model:    tr.Model,
+		Cues:     make([]SubtitleCue, len(orig.Cues)),
+	}
+	for i, o := range orig.Cues {
+		aligned.Cues[i] = SubtitleCue{
+			Index: o.Index,
+			Start: o.Start,
+			End:   o.End,
+			Text:  strings.Join(texts[i], " "),
+		}
+	}
+	return aligned, nil
+}
+
+// ExportBilingual сериализует пару документов в один файл, где под строкой
+// оригинала идёт строка перевода. Поддерживаются форматы srt и ass.
+func (a *App) ExportBilingual(result BilingualResult, format string) (string, error) {
+	if result.Original == nil || result.Translation == nil {
+		return "", errors.New("bilingual result is incomplete")
+	}
+	if len(result.Original.Cues) != len(result.Translation.Cues) {
+		return "", errors.New("translation is not aligned with original")
+	}
+	log.Printf("[ExportBilingual] Экспорт %d реплик в формат %s\n", len(result.Original.Cues), format)
+
+	switch strings.ToLower(format) {
+	case "srt":
+		cues := make([]SubtitleCue, len(result.Original.Cues))
+		for i, o := range result.Original.Cues {
+			cues[i] = o
+			if t := result.Translation.Cues[i].Text; t != "" {
+				cues[i].Text = o.Text + "\n" + t
+			}
+		}
+		return formatSRT(cues), nil
+	case "ass":
+		// Форматтер может пропускать и переставлять реплики, поэтому перевод
+		// ищется по номеру реплики, а не по порядку вызова
+		translations := make(map[int]string, len(result.Original.Cues))
+		for i, o := range result.Original.Cues {
+			translations[o.Index] = result.Translation.Cues[i].Text
+		}
+		return formatASSWith(result.Original, func(c SubtitleCue) string {
+			text := assEscapeText(c.Text)
+			if t := translations[c.Index]; t != "" {
+				// Перевод выводится курсивом и чуть мельче оригинала
+				text += fmt.Sprintf(`\N{\i1\fs36}%s{\r}`, assEscapeText(t))
+			}
+			return text
+		}), nil
+	}
+	return "", errors.New("unknown subtitle format")
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// CaptionStyle оформление вшиваемых в кадр субтитров. Размеры и отступы —
+// в пикселях кадра исходного видео, цвета — #RRGGBB или #RRGGBBAA.
+type CaptionStyle struct {
+	FontName     string  `json:"fontName"`
+	FontSize     int     `json:"fontSize"`
+	Color        string  `json:"color"`
+	OutlineColor string  `json:"outlineColor"`
+	Outline      float64 `json:"outline"`
+	Shadow       float64 `json:"shadow"`
+	Bold         bool    `json:"bold"`
+	Italic       bool    `json:"italic"`
+	Position     string  `json:"position"` // bottom (по умолчанию), middle, top
+	MarginV      int     `json:"marginV"`  // отступ от края кадра по вертикали
+	MarginH      int     `json:"marginH"`  // боковые безопасные поля
+	// Box выводит текст на подложке цвета BoxColor вместо контура
+	Box      bool   `json:"box"`
+	BoxColor string `json:"boxColor"`
+	// Quality пресет перекодирования: fast, balanced (по умолчанию), high
+	Quality string `json:"quality"`
+}
+
+// burnQualityPresets параметры libx264 для пресетов качества
+var burnQualityPresets = map[string][]string{
+	"fast":     {"-preset", "veryfast", "-crf", "23"},
+	"balanced": {"-preset", "medium", "-crf", "20"},
+	"high":     {"-preset", "slow", "-crf", "17"},
+}
+
+// assColor переводит #RRGGBB[AA] в &HAABBGGRR (в ASS альфа — прозрачность)
+func assColor(hex, fallback string) (string, error) {
+	hex = strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	if hex == "" {
+		return fallback, nil
+	}
+	if len(hex) == 6 {
+		hex += "FF"
+	}
+	if len(hex) != 8 {
+		return "", fmt.Errorf("invalid color %q", hex)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return "", fmt.Errorf("invalid color %q", hex)
+	}
+	r, g, b, alpha := v>>24&0xFF, v>>16&0xFF, v>>8&0xFF, v&0xFF
+	return fmt.Sprintf("&H%02X%02X%02X%02X", 0xFF-alpha, b, g, r), nil
+}
+
+// toASS строит стиль ASS для кадра высотой height
+func (s CaptionStyle) toASS(height int) (assStyle, error) {
+	st := defaultASSStyle()
+	// Значения по умолчанию масштабируются от кадра 1080p
+	scale := float64(height) / 1080
+	st.FontSize = int(48 * scale)
+	st.MarginV = int(60 * scale)
+	st.MarginL = int(80 * scale)
+	if s.FontName != "" {
+		st.FontName = s.FontName
+	}
+	if s.FontSize > 0 {
+		st.FontSize = s.FontSize
+	}
+	if s.MarginV > 0 {
+		st.MarginV = s.MarginV
+	}
+	if s.MarginH > 0 {
+		st.MarginL = s.MarginH
+	}
+	st.MarginR = st.MarginL
+	st.Bold, st.Italic = s.Bold, s.Italic
+	st.OutlineSize, st.Shadow = s.Outline, s.Shadow
+	if st.OutlineSize == 0 && !s.Box {
+		st.OutlineSize = math.Round(2*scale*10) / 10
+	}
+
+	var err error
+	if st.Primary, err = assColor(s.Color, st.Primary); err != nil {
+		return st, err
+	}
+	if st.Outline, err = assColor(s.OutlineColor, st.Outline); err != nil {
+		return st, err
+	}
+	if s.Box {
+		st.BorderStyle = 3
+		box, err := assColor(s.BoxColor, "&H80000000")
+		if err != nil {
+			return st, err
+		}
+		// libass рисует подложку цветом контура, VSFilter — цветом тени
+		st.Outline, st.Back = box, box
+		st.OutlineSize = max(st.OutlineSize, math.Round(8*scale))
+	}
+	switch s.Position {
+	case "", "bottom":
+		st.Alignment = 2
+	case "middle":
+		st.Alignment = 5
+	case "top":
+		st.Alignment = 8
+	default:
+		return st, errors.New("unknown caption position")
+	}
+	return st, nil
+}
+
+// probeVideoSize возвращает ширину и высоту первой видеодорожки в том виде,
+// в каком её показывает плеер. Телефоны пишут вертикальное видео как
+// горизонтальное с поворотом в метаданных; ffmpeg при перекодировании
+// поворачивает кадр, поэтому размеры меняются местами.
+func probeVideoSize(filePath string) (int, int, error) {
+	out, err := exec.Command(ffprobePath, "-v", "error", "-select_streams", "v:0",
+		"-show_entries", "stream=width,height:stream_tags=rotate:stream_side_data=rotation",
+		"-of", "json", filePath).Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("ffprobe error: %v", err)
+	}
+	var probe struct {
+		Streams []struct {
+			Width    int               `json:"width"`
+			Height   int               `json:"height"`
+			Tags     map[string]string `json:"tags"`
+			SideData []struct {
+				Rotation float64 `json:"rotation"`
+			} `json:"side_data_list"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil || len(probe.Streams) == 0 || probe.Streams[0].Width <= 0 || probe.Streams[0].Height <= 0 {
+		return 0, 0, fmt.Errorf("ffprobe: cannot parse video size %q", strings.TrimSpace(string(out)))
+	}
+	st := probe.Streams[0]
+	var rotation float64
+	if r, err := strconv.ParseFloat(st.Tags["rotate"], 64); err == nil {
+		rotation = r
+	}
+	for _, sd := range st.SideData {
+		if sd.Rotation != 0 {
+			rotation = sd.Rotation
+		}
+	}
+	w, h := displaySize(st.Width, st.Height, rotation)
+	return w, h, nil
+}
+
+// displaySize меняет ширину и высоту местами при повороте на ±90°
+func displaySize(w, h int, rotation float64) (int, int) {
+	if r := int(math.Round(rotation)) % 180; r == 90 || r == -90 {
+		return h, w
+	}
+	return w, h
+}
+
+// BurnSubtitles вшивает субтитры в кадр (hardsub) фильтром ass с оформлением
+// style и перекодирует видео с пресетом style.Quality.
+// Прогресс отправляется событием "burnProgress"; задачу можно прервать через
+// CancelJob(outPath).
+func (a *App) BurnSubtitles(videoPath string, doc SubtitleDocument, style CaptionStyle, outPath string) (string, error) {
+	log.Printf("[BurnSubtitles] Вшивание субтитров: %s -> %s, качество=%s\n", videoPath, outPath, style.Quality)
+	width, height, err := probeVideoSize(videoPath)
+	if err != nil {
+		log.Printf("[BurnSubtitles] %v\n", err)
+		return "", err
+	}
+	base, err := style.toASS(height)
+	if err != nil {
+		return "", err
+	}
+	script := formatASSStyled(&doc, base, width, height, func(c SubtitleCue) string { return assEscapeText(c.Text) })
+	return a.renderASS(videoPath, script, outPath, style.Quality)
+}
+
+// renderASS перекодирует видео, накладывая готовый ASS-скрипт
+func (a *App) renderASS(videoPath, script, outPath, quality string) (string, error) {
+	if quality == "" {
+		quality = "balanced"
+	}
+	preset, ok := burnQualityPresets[quality]
+	if !ok {
+		return "", errors.New("unknown quality preset")
+	}
+	videoAbs, err := filepath.Abs(videoPath)
+	if err != nil {
+		return "", err
+	}
+	outAbs, err := filepath.Abs(outPath)
+	if err != nil {
+		return "", err
+	}
+	if outAbs == videoAbs {
+		return "", errors.New("output path matches the source file")
+	}
+	if _, err := os.Stat(outAbs); err == nil {
+		return "", errors.New("Файл уже существует: " + outPath)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "submagic_burn_")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+	// ffmpeg запускается во временном каталоге, и фильтр получает имя без
+	// пути: экранировать путь в синтаксисе фильтров (двоеточия, кавычки,
+	// обратные слэши) ненадёжно
+	if err := os.WriteFile(filepath.Join(tmpDir, "subs.ass"), []byte(script), 0644); err != nil {
+		return "", err
+	}
+
+	ctx, done, err := a.startJob(outPath)
+	if err != nil {
+		return "", err
+	}
+	defer done()
+
+	// yuv420p: H.264 из 10-битных и 4:4:4 исходников многие плееры не открывают
+	args := []string{"-i", videoAbs, "-vf", "ass=subs.ass", "-c:v", "libx264", "-pix_fmt", "yuv420p"}
+	args = append(args, preset...)
+	args = append(args, "-c:a", "copy", "-n", outAbs)
+	duration, err := probeMediaDuration(videoAbs)
+	if err != nil {
+		log.Printf("[BurnSubtitles] Длительность неизвестна, прогресс не будет показан: %v\n", err)
+	}
+	err = runFFmpegProgressIn(ctx, tmpDir, args, duration, func(percent float64) {
+		if a.ctx != nil {
+			runtime.EventsEmit(a.ctx, "burnProgress", map[string]interface{}{
+				"file":    outPath,
+				"percent": int(percent),
+			})
+		}
+	})
+	if err != nil {
+		log.Printf("[BurnSubtitles] Ошибка: %v\n", err)
+		_ = os.Remove(outAbs) // недописанный файл бесполезен
+		return "", err
+	}
+	log.Printf("[BurnSubtitles] Готово: %s\n", outPath)
+	return outPath, nil
+}
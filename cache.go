@@ -0,0 +1,266 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultCacheMaxBytes предел размера кэша распознавания по умолчанию
+const defaultCacheMaxBytes = 512 << 20
+
+// CacheSettings настройки кэша распознавания
+type CacheSettings struct {
+	// Disabled отключает кэш: whisper-cli запускается всегда
+	Disabled bool `json:"disabled"`
+	// MaxBytes предел размера кэша; 0 — по умолчанию (512 МБ)
+	MaxBytes int64 `json:"maxBytes"`
+}
+
+// withDefaults подставляет значения по умолчанию
+func (c CacheSettings) withDefaults() CacheSettings {
+	if c.MaxBytes <= 0 {
+		c.MaxBytes = defaultCacheMaxBytes
+	}
+	return c
+}
+
+// CacheStats состояние кэша распознавания
+type CacheStats struct {
+	Entries  int   `json:"entries"`
+	Bytes    int64 `json:"bytes"`
+	MaxBytes int64 `json:"maxBytes"`
+	Disabled bool  `json:"disabled"`
+	// Hits и Misses считаются с запуска приложения
+	Hits   int `json:"hits"`
+	Misses int `json:"misses"`
+}
+
+// transcriptionCache хранит JSON-вывод whisper-cli в файлах <ключ>.json.
+// Ключ — хэш извлечённого WAV, модели и опций, влияющих на вывод, поэтому
+// один и тот же звук распознаётся повторно только при смене модели или
+// опций. Кэшируется каждый кусок отдельно: после сбоя на середине длинного
+// файла уже готовые куски при повторном запуске берутся из кэша.
+// Вытесняются давно не использованные записи: время доступа хранится
+// в mtime файла.
+type transcriptionCache struct {
+	mu     sync.Mutex
+	hits   int
+	misses int
+}
+
+// getCacheDir возвращает каталог кэша рядом с каталогом моделей
+func (a *App) getCacheDir() string {
+	return filepath.Join(filepath.Dir(a.modelsDir), "cache")
+}
+
+// whisperCacheKey вычисляет ключ кэша для WAV-файла. Из опций учитываются
+// только те, что передаются whisper-cli (см. whisperArgs): пословные метки
+// и фильтр галлюцинаций применяются к уже полученному выводу.
+func whisperCacheKey(wavPath, modelPath string, opts TranscribeOptions) (string, error) {
+	f, err := os.Open(wavPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	// Модель определяется файлом: имя и размер меняются при замене весов
+	var modelSize int64
+	if info, err := os.Stat(modelPath); err == nil {
+		modelSize = info.Size()
+	}
+	fmt.Fprintf(h, "\x00%s:%d\x00%s:%t:%t", filepath.Base(modelPath), modelSize, opts.Lang, opts.Diarize, opts.Translate)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cacheSettings читает настройки кэша; при ошибке используются значения по умолчанию
+func (a *App) cacheSettings() CacheSettings {
+	settings, err := a.loadSettings()
+	if err != nil {
+		return CacheSettings{}.withDefaults()
+	}
+	return settings.Cache.withDefaults()
+}
+
+// cacheLookup возвращает сохранённый вывод whisper-cli или nil
+func (a *App) cacheLookup(key string) []byte {
+	c := &a.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	path := filepath.Join(a.getCacheDir(), key+".json")
+	data, err := os.ReadFile(path)
+	if err != nil || !json.Valid(data) {
+		c.misses++
+		return nil
+	}
+	c.hits++
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return data
+}
+
+// cacheStore сохраняет вывод whisper-cli и вытесняет старые записи сверх
+// maxBytes. Ошибки только логируются: кэш не должен ломать распознавание.
+func (a *App) cacheStore(key string, data []byte, maxBytes int64) {
+	c := &a.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dir := a.getCacheDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Printf("[cacheStore] Ошибка создания каталога кэша: %v\n", err)
+		return
+	}
+	// Запись через временный файл, чтобы не оставить недописанную запись
+	tmp, err := os.CreateTemp(dir, "tmp-*")
+	if err != nil {
+		log.Printf("[cacheStore] %v\n", err)
+		return
+	}
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		os.Remove(tmp.Name())
+		log.Printf("[cacheStore] Ошибка записи: %v\n", errors.Join(werr, cerr))
+		return
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, key+".json")); err != nil {
+		os.Remove(tmp.Name())
+		log.Printf("[cacheStore] %v\n", err)
+		return
+	}
+	if removed := evictCache(dir, maxBytes); removed > 0 {
+		log.Printf("[cacheStore] Вытеснено записей: %d\n", removed)
+	}
+}
+
+type cacheEntry struct {
+	path     string
+	size     int64
+	accessed time.Time
+}
+
+// cacheEntries перечисляет записи кэша
+func cacheEntries(dir string) ([]cacheEntry, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var entries []cacheEntry
+	for _, e := range dirEntries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, cacheEntry{filepath.Join(dir, e.Name()), info.Size(), info.ModTime()})
+	}
+	return entries, nil
+}
+
+// evictCache удаляет давно не использованные записи, пока кэш больше
+// maxBytes, и возвращает число удалённых
+func evictCache(dir string, maxBytes int64) int {
+	entries, err := cacheEntries(dir)
+	if err != nil {
+		log.Printf("[evictCache] %v\n", err)
+		return 0
+	}
+	var total int64
+	for _, e := range entries {
+		total += e.size
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].accessed.Before(entries[j].accessed) })
+	removed := 0
+	for _, e := range entries {
+		if total <= maxBytes {
+			break
+		}
+		if err := os.Remove(e.path); err != nil {
+			log.Printf("[evictCache] %v\n", err)
+			continue
+		}
+		total -= e.size
+		removed++
+	}
+	return removed
+}
+
+// GetCacheStats возвращает размер и статистику кэша распознавания
+func (a *App) GetCacheStats() (CacheStats, error) {
+	settings := a.cacheSettings()
+	a.cache.mu.Lock()
+	defer a.cache.mu.Unlock()
+	entries, err := cacheEntries(a.getCacheDir())
+	if err != nil {
+		log.Printf("[GetCacheStats] %v\n", err)
+		return CacheStats{}, err
+	}
+	stats := CacheStats{
+		Entries:  len(entries),
+		MaxBytes: settings.MaxBytes,
+		Disabled: settings.Disabled,
+		Hits:     a.cache.hits,
+		Misses:   a.cache.misses,
+	}
+	for _, e := range entries {
+		stats.Bytes += e.size
+	}
+	return stats, nil
+}
+
+// SetCacheSettings сохраняет настройки кэша; при уменьшении предела лишние
+// записи вытесняются сразу
+func (a *App) SetCacheSettings(cache CacheSettings) error {
+	log.Printf("[SetCacheSettings] %+v\n", cache)
+	if cache.MaxBytes < 0 {
+		return errors.New("cache size limit must not be negative")
+	}
+	err := a.updateSettings(func(settings *Settings) error {
+		settings.Cache = cache
+		return nil
+	})
+	if err != nil {
+		log.Printf("[SetCacheSettings] Ошибка сохранения настроек: %v\n", err)
+		return err
+	}
+	a.cache.mu.Lock()
+	defer a.cache.mu.Unlock()
+	evictCache(a.getCacheDir(), cache.withDefaults().MaxBytes)
+	return nil
+}
+
+// ClearTranscriptionCache удаляет все записи кэша распознавания
+func (a *App) ClearTranscriptionCache() error {
+	log.Println("[ClearTranscriptionCache] Очистка кэша")
+	a.cache.mu.Lock()
+	defer a.cache.mu.Unlock()
+	entries, err := cacheEntries(a.getCacheDir())
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := os.Remove(e.path); err != nil {
+			log.Printf("[ClearTranscriptionCache] %v\n", err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// CaptionTemplate шаблон «социальных» субтитров: короткие группы слов с
+// анимацией произносимого слова. Размеры в Style заданы для кадра, короткая
+// сторона которого 1080 пикселей, и масштабируются под реальный кадр.
+type CaptionTemplate struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Style       CaptionStyle `json:"style"`
+	// MaxWords и MaxChars ограничивают группу слов на экране
+	MaxWords int `json:"maxWords"`
+	MaxChars int `json:"maxChars"`
+	// Animation выделение текущего слова: highlight — цвет, pop — цвет и
+	// «выпрыгивание», reveal — слова появляются по мере произнесения, none
+	Animation      string `json:"animation"`
+	HighlightColor string `json:"highlightColor,omitempty"`
+	Uppercase      bool   `json:"uppercase"`
+	// Keywords слова, выделяемые цветом EmphasisColor; непустое значение —
+	// эмодзи, который выводится после слова
+	Keywords      map[string]string `json:"keywords,omitempty"`
+	EmphasisColor string            `json:"emphasisColor,omitempty"`
+	BuiltIn       bool              `json:"builtIn"`
+}
+
+// captionTemplates встроенные шаблоны
+var captionTemplates = []CaptionTemplate{
+	{
+		Name: "punchy", Description: "1–2 крупных слова по центру, выпрыгивающее жёлтое слово",
+		Style:    CaptionStyle{FontName: "Arial Black", FontSize: 96, Bold: true, Outline: 6, Shadow: 2, Position: "middle", MarginH: 60},
+		MaxWords: 2, MaxChars: 16, Animation: "pop", HighlightColor: "#FFE000", Uppercase: true, EmphasisColor: "#FF4040",
+	},
+	{
+		Name: "highlight", Description: "До 3 слов внизу, текущее слово подсвечено зелёным",
+		Style:    CaptionStyle{FontName: "Montserrat", FontSize: 72, Bold: true, Outline: 5, Shadow: 1, MarginV: 320, MarginH: 80},
+		MaxWords: 3, MaxChars: 22, Animation: "highlight", HighlightColor: "#39E75F", Uppercase: true, EmphasisColor: "#FFE000",
+	},
+	{
+		Name: "reveal", Description: "Слова появляются по мере произнесения",
+		Style:    CaptionStyle{FontName: "Arial", FontSize: 68, Bold: true, Outline: 4, Shadow: 1, MarginV: 320, MarginH: 80},
+		MaxWords: 3, MaxChars: 24, Animation: "reveal", EmphasisColor: "#FFB000",
+	},
+	{
+		Name: "minimal", Description: "Короткие фразы на полупрозрачной подложке без анимации",
+		Style:    CaptionStyle{FontName: "Arial", FontSize: 56, Box: true, BoxColor: "#000000A0", MarginV: 260, MarginH: 80},
+		MaxWords: 3, MaxChars: 26, Animation: "none",
+	},
+}
+
+// ListCaptionTemplates возвращает встроенные и пользовательские шаблоны
+func (a *App) ListCaptionTemplates() ([]CaptionTemplate, error) {
+	settings, err := a.loadSettings()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]CaptionTemplate, 0, len(captionTemplates)+len(settings.CaptionTemplates))
+	for _, t := range captionTemplates {
+		t.BuiltIn = true
+		result = append(result, t)
+	}
+	user := append([]CaptionTemplate(nil), settings.CaptionTemplates...)
+	sort.Slice(user, func(i, j int) bool { return user[i].Name < user[j].Name })
+	return append(result, user...), nil
+}
+
+// findCaptionTemplate ищет шаблон по имени
+func (a *App) findCaptionTemplate(name string) (*CaptionTemplate, error) {
+	templates, err := a.ListCaptionTemplates()
+	if err != nil {
+		return nil, err
+	}
+	for i := range templates {
+		if templates[i].Name == name {
+			return &templates[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown caption template %q", name)
+}
+
+func validateCaptionTemplate(t *CaptionTemplate) error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return errors.New("template name is empty")
+	}
+	switch t.Animation {
+	case "":
+		t.Animation = "highlight"
+	case "highlight", "pop", "reveal", "none":
+	default:
+		return fmt.Errorf("unknown animation %q", t.Animation)
+	}
+	if t.MaxWords <= 0 {
+		t.MaxWords = 3
+	}
+	if t.MaxChars <= 0 {
+		t.MaxChars = 24
+	}
+	keywords := make(map[string]string, len(t.Keywords))
+	for k, emoji := range t.Keywords {
+		if k = normalizeCueText(k); k != "" {
+			keywords[k] = strings.TrimSpace(emoji)
+		}
+	}
+	t.Keywords = keywords
+	// Проверяем цвета и положение заранее, а не при первом рендере
+	if _, err := t.Style.toASS(1080); err != nil {
+		return err
+	}
+	for _, c := range []string{t.HighlightColor, t.EmphasisColor} {
+		if _, err := assColor(c, ""); err != nil {
+			return err
+		}
+	}
+	t.BuiltIn = false
+	return nil
+}
+
+// SaveCaptionTemplate добавляет или заменяет пользовательский шаблон.
+// Имена встроенных шаблонов заняты.
+func (a *App) SaveCaptionTemplate(t CaptionTemplate) error {
+	log.Printf("[SaveCaptionTemplate] Сохранение шаблона %q\n", t.Name)
+	if err := validateCaptionTemplate(&t); err != nil {
+		return err
+	}
+	for _, b := range captionTemplates {
+		if b.Name == t.Name {
+			return fmt.Errorf("template %q is built in", t.Name)
+		}
+	}
+	return a.updateSettings(func(settings *Settings) error {
+		for i := range settings.CaptionTemplates {
+			if settings.CaptionTemplates[i].Name == t.Name {
+				settings.CaptionTemplates[i] = t
+				return nil
+			}
+		}
+		settings.CaptionTemplates = append(settings.CaptionTemplates, t)
+		return nil
+	})
+}
+
+// ImportCaptionTemplate загружает пользовательский шаблон из JSON-файла
+func (a *App) ImportCaptionTemplate(path string) (*CaptionTemplate, error) {
+	log.Printf("[ImportCaptionTemplate] Импорт %s\n", path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var t CaptionTemplate
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("invalid template file: %v", err)
+	}
+	if err := a.SaveCaptionTemplate(t); err != nil {
+		return nil, err
+	}
+	return a.findCaptionTemplate(strings.TrimSpace(t.Name))
+}
+
+// DeleteCaptionTemplate удаляет пользовательский шаблон
+func (a *App) DeleteCaptionTemplate(name string) error {
+	log.Printf("[DeleteCaptionTemplate] Удаление шаблона %q\n", name)
+	return a.updateSettings(func(settings *Settings) error {
+		kept := settings.CaptionTemplates[:0]
+		for _, t := range settings.CaptionTemplates {
+			if t.Name != name {
+				kept = append(kept, t)
+			}
+		}
+		if len(kept) == len(settings.CaptionTemplates) {
+			return fmt.Errorf("unknown caption template %q", name)
+		}
+		settings.CaptionTemplates = kept
+		return nil
+	})
+}
+
+// captionWords собирает слова документа. Для реплик без пословных меток
+// время слов оценивается пропорционально их длине.
+func captionWords(doc *SubtitleDocument) []Word {
+	var words []Word
+	for _, c := range doc.Cues {
+		if len(c.Words) > 0 {
+			words = append(words, c.Words...)
+			continue
+		}
+		fields := strings.Fields(c.Text)
+		total := 0
+		for _, f := range fields {
+			total += utf8.RuneCountInString(f) + 1
+		}
+		pos := 0
+		for _, f := range fields {
+			n := utf8.RuneCountInString(f) + 1
+			words = append(words, Word{
+				Text:  f,
+				Start: c.Start + (c.End-c.Start)*int64(pos)/int64(total),
+				End:   c.Start + (c.End-c.Start)*int64(pos+n-1)/int64(total),
+			})
+			pos += n
+		}
+	}
+	return words
+}
+
+// groupPunchy разбивает слова на короткие группы: не больше maxWords слов и
+// maxChars символов, с разрывом на знаках препинания и паузах
+func groupPunchy(words []Word, maxWords, maxChars int) [][]Word {
+	const maxPause = 400
+	var groups [][]Word
+	var cur []Word
+	chars := 0
+	for _, w := range words {
+		n := utf8.RuneCountInString(w.Text)
+		if len(cur) > 0 && (len(cur) >= maxWords || chars+1+n > maxChars || w.Start-cur[len(cur)-1].End > maxPause) {
+			groups = append(groups, cur)
+			cur, chars = nil, 0
+		}
+		cur = append(cur, w)
+		chars += n + 1
+		if endsSentence(w.Text) || endsClause(w.Text) {
+			groups = append(groups, cur)
+			cur, chars = nil, 0
+		}
+	}
+	if len(cur) > 0 {
+		groups = append(groups, cur)
+	}
+	return groups
+}
+
+// captionCues строит по группе слов реплики-кадры: по одной на каждое слово,
+// в которой это слово выделено анимацией шаблона
+func captionCues(group []Word, groupEnd int64, t *CaptionTemplate, primary string) []SubtitleCue {
+	highlight, _ := assColor(t.HighlightColor, "&H0000E0FF")
+	emphasis, _ := assColor(t.EmphasisColor, "")
+	texts := make([]string, len(group))
+	for i, w := range group {
+		text := w.Text
+		if t.Uppercase {
+			text = strings.ToUpper(text)
+		}
+		text = assEscapeText(text)
+		emoji, keyword := t.Keywords[normalizeCueText(w.Text)]
+		if keyword && emphasis != "" {
+			text = fmt.Sprintf(`{\c%s}%s{\c%s}`, emphasis, text, primary)
+		}
+		if emoji != "" {
+			text += " " + emoji
+		}
+		texts[i] = text
+	}
+	if t.Animation == "none" {
+		return []SubtitleCue{{Start: group[0].Start, End: groupEnd, Text: strings.Join(texts, " ")}}
+	}
+
+	cues := make([]SubtitleCue, 0, len(group))
+	for i, w := range group {
+		end := groupEnd
+		if i+1 < len(group) {
+			end = group[i+1].Start
+		}
+		if end <= w.Start {
+			continue
+		}
+		parts := make([]string, len(group))
+		for j := range group {
+			switch {
+			case j == i && t.Animation == "pop":
+				parts[j] = fmt.Sprintf(`{\c%s\fscx80\fscy80\t(0,90,\fscx112\fscy112)\t(90,160,\fscx100\fscy100)}%s{\r}`, highlight, texts[j])
+			case j == i && t.Animation == "highlight":
+				parts[j] = fmt.Sprintf(`{\c%s}%s{\c%s}`, highlight, texts[j], primary)
+			case j > i && t.Animation == "reveal":
+				// Невидимое, но занимающее место слово: строка не прыгает
+				parts[j] = `{\alpha&HFF&}` + texts[j] + `{\alpha&H00&}`
+			default:
+				parts[j] = texts[j]
+			}
+		}
+		start := w.Start
+		if i == 0 {
+			start = group[0].Start
+		}
+		cues = append(cues, SubtitleCue{Start: start, End: end, Text: strings.Join(parts, " ")})
+	}
+	return cues
+}
+
+// formatCaptionsASS сериализует документ в анимированный ASS по шаблону
+func formatCaptionsASS(doc *SubtitleDocument, t *CaptionTemplate, width, height int) (string, error) {
+	style := t.Style
+	scale := float64(min(width, height)) / 1080
+	style.FontSize = int(float64(style.FontSize) * scale)
+	style.MarginV = int(float64(style.MarginV) * scale)
+	style.MarginH = int(float64(style.MarginH) * scale)
+	style.Outline *= scale
+	style.Shadow *= scale
+	base, err := style.toASS(height)
+	if err != nil {
+		return "", err
+	}
+
+	groups := groupPunchy(captionWords(doc), t.MaxWords, t.MaxChars)
+	var cues []SubtitleCue
+	for i, g := range groups {
+		// Группа держится до начала следующей, но не дольше секунды после
+		// последнего слова
+		end := g[len(g)-1].End + 1000
+		if i+1 < len(groups) {
+			end = min(end, groups[i+1][0].Start)
+		}
+		end = max(end, g[len(g)-1].End)
+		cues = append(cues, captionCues(g, end, t, base.Primary)...)
+	}
+	renumberCues(cues)
+	// Цвета дикторов перебили бы подсветку слов
+	out := SubtitleDocument{Language: doc.Language, Cues: cues}
+	return formatASSStyled(&out, base, width, height, func(c SubtitleCue) string { return c.Text }), nil
+}
+
+// RenderCaptions возвращает ASS с анимированными субтитрами по шаблону
+// templateName для кадра width×height (для вертикального видео 1080×1920)
+func (a *App) RenderCaptions(doc SubtitleDocument, templateName string, width int, height int) (string, error) {
+	log.Printf("[RenderCaptions] Шаблон %q, кадр %dx%d\n", templateName, width, height)
+	t, err := a.findCaptionTemplate(templateName)
+	if err != nil {
+		return "", err
+	}
+	if width <= 0 || height <= 0 {
+		width, height = 1080, 1920
+	}
+	return formatCaptionsASS(&doc, t, width, height)
+}
+
+// BurnCaptions вшивает в кадр анимированные субтитры по шаблону templateName.
+// Прогресс и отмена — как у BurnSubtitles.
+func (a *App) BurnCaptions(videoPath string, doc SubtitleDocument, templateName string, outPath string) (string, error) {
+	log.Printf("[BurnCaptions] Шаблон %q: %s -> %s\n", templateName, videoPath, outPath)
+	t, err := a.findCaptionTemplate(templateName)
+	if err != nil {
+		return "", err
+	}
+	width, height, err := probeVideoSize(videoPath)
+	if err != nil {
+		log.Printf("[BurnCaptions] %v\n", err)
+		return "", err
+	}
+	script, err := formatCaptionsASS(&doc, t, width, height)
+	if err != nil {
+		return "", err
+	}
+	return a.renderASS(videoPath, script, outPath, t.Style.Quality)
+}
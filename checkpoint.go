@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// Файлы длиннее порога распознаются кусками с сохранением промежуточных
+	// результатов, чтобы сбой не уничтожал часы работы
+	checkpointThreshold = 10 * 60
+	// Длина куска задачи, с
+	checkpointChunkSeconds = 5 * 60
+)
+
+// TranscriptionJob план распознавания длинного файла. Хранится в
+// jobs/<id>/job.json, реплики готовых кусков — в jobs/<id>/chunk-NNNN.json.
+// Каталог задачи удаляется после успешного завершения, поэтому всё, что в
+// нём осталось, — прерванные задачи.
+type TranscriptionJob struct {
+	ID        string            `json:"id"`
+	Input     string            `json:"input"`
+	InputHash string            `json:"inputHash"`
+	Options   TranscribeOptions `json:"options"`
+	Chunks    []ChunkBoundary   `json:"chunks"`
+	Created   time.Time         `json:"created"`
+	// Completed число готовых кусков; определяется по файлам при чтении
+	Completed int `json:"completed"`
+	// Error последняя ошибка, на которой задача остановилась
+	Error string `json:"error,omitempty"`
+	// PID процесса, который сейчас выполняет задачу; 0 — задача остановлена
+	PID int `json:"pid,omitempty"`
+}
+
+// getJobsDir возвращает каталог задач рядом с настройками
+func (a *App) getJobsDir() string {
+	return filepath.Join(filepath.Dir(a.getSettingsPath()), "jobs")
+}
+
+func (a *App) jobDir(id string) string {
+	return filepath.Join(a.getJobsDir(), id)
+}
+
+func chunkCheckpointPath(dir string, i int) string {
+	return filepath.Join(dir, fmt.Sprintf("chunk-%04d.json", i))
+}
+
+// writeFileAtomic пишет файл через временный, чтобы при аварийном
+// завершении не осталось недописанного чекпоинта
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func (a *App) saveTranscriptionJob(job *TranscriptionJob) error {
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(a.jobDir(job.ID), "job.json"), data)
+}
+
+// newTranscriptionJob планирует куски файла и сохраняет план задачи
+func (a *App) newTranscriptionJob(filePath string, opts TranscribeOptions) (*TranscriptionJob, error) {
+	chunks, err := a.PlanChunks(filePath, checkpointChunkSeconds)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := quickFileHash(filePath)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	job := &TranscriptionJob{
+		ID:        fmt.Sprintf("job-%d", now.UnixNano()),
+		Input:     filePath,
+		InputHash: hash,
+		Options:   opts,
+		Chunks:    chunks,
+		Created:   now,
+		PID:       os.Getpid(),
+	}
+	if err := os.MkdirAll(a.jobDir(job.ID), 0755); err != nil {
+		return nil, err
+	}
+	if err := a.saveTranscriptionJob(job); err != nil {
+		return nil, err
+	}
+	log.Printf("[newTranscriptionJob] Задача %s: %d кусков\n", job.ID, len(chunks))
+	return job, nil
+}
+
+// loadTranscriptionJob читает план задачи и считает готовые куски
+func (a *App) loadTranscriptionJob(id string) (*TranscriptionJob, error) {
+	if id == "" || filepath.Base(id) != id || strings.HasPrefix(id, ".") {
+		return nil, errors.New("invalid job id")
+	}
+	dir := a.jobDir(id)
+	data, err := os.ReadFile(filepath.Join(dir, "job.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New("job not found")
+		}
+		return nil, err
+	}
+	var job TranscriptionJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("job %s is corrupted: %w", id, err)
+	}
+	job.Completed = 0
+	for i := range job.Chunks {
+		if _, err := os.Stat(chunkCheckpointPath(dir, i)); err == nil {
+			job.Completed++
+		}
+	}
+	return &job, nil
+}
+
+// runTranscriptionJob распознаёт недостающие куски задачи, сохраняя реплики
+// каждого сразу после готовности, и возвращает реплики всего файла. Задачу
+// можно отменить через CancelJob(job.ID). При успехе каталог задачи удаляется.
+func (a *App) runTranscriptionJob(job *TranscriptionJob, modelPath string) ([]SubtitleCue, error) {
+	ctx, done, err := a.startJob(job.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	// Задача помечается как занятая, чтобы ListIncompleteJobs не предложил
+	// продолжить её, пока она выполняется
+	if job.PID != os.Getpid() {
+		job.PID = os.Getpid()
+		if err := a.saveTranscriptionJob(job); err != nil {
+			return nil, err
+		}
+	}
+	dir := a.jobDir(job.ID)
+	var cues []SubtitleCue
+	for i, chunk := range job.Chunks {
+		path := chunkCheckpointPath(dir, i)
+		if data, err := os.ReadFile(path); err == nil {
+			var saved []SubtitleCue
+			if err := json.Unmarshal(data, &saved); err == nil {
+				cues = append(cues, saved...)
+				a.emitTranscriptionProgress(job.Input, i+1, len(job.Chunks))
+				continue
+			}
+			log.Printf("[runTranscriptionJob] Повреждённый чекпоинт %s, кусок распознаётся заново\n", path)
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, a.failTranscriptionJob(job, errors.New("transcription cancelled"))
+		}
+
+		chunkCues, err := a.transcribeSegment(job.Input, modelPath, chunk.Start, chunk.End, job.Options)
+		if err != nil {
+			log.Printf("[runTranscriptionJob] Ошибка куска %d (%.1f-%.1f): %v\n", i, chunk.Start, chunk.End, err)
+			return nil, a.failTranscriptionJob(job, err)
+		}
+		shiftCues(chunkCues, int64(math.Round(chunk.Start*1000)))
+		if chunkCues == nil {
+			chunkCues = []SubtitleCue{}
+		}
+		data, err := json.Marshal(chunkCues)
+		if err == nil {
+			err = writeFileAtomic(path, data)
+		}
+		if err != nil {
+			log.Printf("[runTranscriptionJob] Ошибка сохранения чекпоинта: %v\n", err)
+			return nil, a.failTranscriptionJob(job, err)
+		}
+		cues = append(cues, chunkCues...)
+		a.emitTranscriptionProgress(job.Input, i+1, len(job.Chunks))
+	}
+
+	sort.SliceStable(cues, func(i, j int) bool { return cues[i].Start < cues[j].Start })
+	renumberCues(cues)
+	if err := os.RemoveAll(dir); err != nil {
+		log.Printf("[runTranscriptionJob] Каталог задачи не удалён: %v\n", err)
+	}
+	return cues, nil
+}
+
+// failTranscriptionJob запоминает ошибку в плане задачи и возвращает её
+func (a *App) failTranscriptionJob(job *TranscriptionJob, err error) error {
+	job.Error = err.Error()
+	job.PID = 0
+	if saveErr := a.saveTranscriptionJob(job); saveErr != nil {
+		log.Printf("[failTranscriptionJob] %v\n", saveErr)
+	}
+	return err
+}
+
+// ListIncompleteJobs возвращает прерванные задачи распознавания, новые первыми.
+// Задачи, которые выполняет этот же процесс, не прерваны и не возвращаются.
+func (a *App) ListIncompleteJobs() ([]TranscriptionJob, error) {
+	entries, err := os.ReadDir(a.getJobsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []TranscriptionJob{}, nil
+		}
+		log.Printf("[ListIncompleteJobs] %v\n", err)
+		return nil, err
+	}
+	jobs := []TranscriptionJob{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		job, err := a.loadTranscriptionJob(e.Name())
+		if err != nil {
+			log.Printf("[ListIncompleteJobs] Пропущена задача %s: %v\n", e.Name(), err)
+			continue
+		}
+		if job.PID == os.Getpid() {
+			continue
+		}
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Created.After(jobs[j].Created) })
+	return jobs, nil
+}
+
+// ResumeJob продолжает прерванную задачу: готовые куски берутся с диска,
+// распознаются только оставшиеся. Результат сохраняется как в GenerateSubtitles.
+func (a *App) ResumeJob(id string) (_ string, err error) {
+	log.Printf("[ResumeJob] Продолжение задачи %s\n", id)
+	job, err := a.loadTranscriptionJob(id)
+	if err != nil {
+		log.Printf("[ResumeJob] %v\n", err)
+		return "", err
+	}
+	// Куски привязаны ко времени исходного файла: после его изменения они
+	// не годятся
+	if hash, err := quickFileHash(job.Input); err != nil {
+		return "", fmt.Errorf("input file is unavailable: %v", err)
+	} else if hash != job.InputHash {
+		return "", errors.New("input file has changed since the job was started")
+	}
+	run := a.startHistory("full", job.Input, job.Options)
+	defer func() { a.finishHistory(run, err) }()
+
+	modelPath, err := a.resolveModelPath(job.Options.Model)
+	if err != nil {
+		log.Printf("[ResumeJob] %v\n", err)
+		return "", err
+	}
+	log.Printf("[ResumeJob] Готово кусков: %d из %d\n", job.Completed, len(job.Chunks))
+	cues, err := a.runTranscriptionJob(job, modelPath)
+	if err != nil {
+		return "", err
+	}
+	doc := SubtitleDocument{Language: job.Options.Lang, Model: job.Options.Model, Cues: cues}
+	return a.saveGeneratedSubtitles(job.Input, &doc, run), nil
+}
+
+// DiscardJob удаляет прерванную задачу вместе с готовыми кусками
+func (a *App) DiscardJob(id string) error {
+	log.Printf("[DiscardJob] Удаление задачи %s\n", id)
+	if _, err := a.loadTranscriptionJob(id); err != nil {
+		return err
+	}
+	a.jobsMu.Lock()
+	_, busy := a.jobs[id]
+	a.jobsMu.Unlock()
+	if busy {
+		return errors.New("job is running")
+	}
+	return os.RemoveAll(a.jobDir(id))
+}
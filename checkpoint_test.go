@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestListIncompleteJobsSkipsRunning(t *testing.T) {
+	chdirTemp(t)
+	a := NewApp()
+	now := time.Now()
+	for _, job := range []*TranscriptionJob{
+		{ID: "job-running", Created: now, PID: os.Getpid()},
+		{ID: "job-crashed", Created: now.Add(-time.Hour), PID: os.Getpid() + 1},
+		{ID: "job-failed", Created: now.Add(-2 * time.Hour), PID: os.Getpid()},
+	} {
+		if err := os.MkdirAll(a.jobDir(job.ID), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if job.ID == "job-failed" {
+			a.failTranscriptionJob(job, errors.New("whisper-cli failed"))
+			continue
+		}
+		if err := a.saveTranscriptionJob(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	jobs, err := a.ListIncompleteJobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+	if len(ids) != 2 || ids[0] != "job-crashed" || ids[1] != "job-failed" {
+		t.Errorf("incomplete jobs = %v, want [job-crashed job-failed]", ids)
+	}
+}
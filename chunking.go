@@ -0,0 +1,192 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// Порог и минимальная длина паузы, по которым ищутся границы кусков
+	chunkSilenceNoiseDb     = -35.0
+	chunkSilenceMinDuration = 0.3
+	// Граница ищется в окне target*(1±chunkSearchWindow) от начала куска
+	chunkSearchWindow = 0.5
+	// Сколько кусков транскрибируется одновременно по умолчанию
+	defaultChunkWorkers = 2
+)
+
+// ChunkBoundary границы куска для транскрибации, в секундах
+type ChunkBoundary struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// PlanChunks предлагает разбиение файла на куски длиной около targetSeconds
+// с разрезами посередине пауз, чтобы не резать слова.
+func (a *App) PlanChunks(filePath string, targetSeconds int) ([]ChunkBoundary, error) {
+	log.Printf("[PlanChunks] Планирование кусков: файл=%s, цель=%d сек\n", filePath, targetSeconds)
+	if targetSeconds <= 0 {
+		return nil, errors.New("targetSeconds must be positive")
+	}
+	duration, err := probeMediaDuration(filePath)
+	if err != nil {
+		log.Printf("[PlanChunks] Ошибка определения длительности: %v\n", err)
+		return nil, err
+	}
+	silences, err := detectSilences(filePath, chunkSilenceNoiseDb, chunkSilenceMinDuration)
+	if err != nil {
+		log.Printf("[PlanChunks] Ошибка поиска пауз: %v\n", err)
+		return nil, err
+	}
+	chunks := planChunkBoundaries(duration, silences, float64(targetSeconds))
+	log.Printf("[PlanChunks] Длительность %.1f сек, пауз %d, кусков %d\n", duration, len(silences), len(chunks))
+	return chunks, nil
+}
+
+// planChunkBoundaries режет [0, duration] на куски около target секунд.
+// Из пауз в окне поиска выбирается ближайшая к target, при равенстве — более длинная;
+// если пауз нет, кусок режется ровно по target.
+func planChunkBoundaries(duration float64, silences []SilenceInterval, target float64) []ChunkBoundary {
+	var chunks []ChunkBoundary
+	cur := 0.0
+	for duration-cur > target*(1+chunkSearchWindow) {
+		ideal := cur + target
+		lo := cur + target*(1-chunkSearchWindow)
+		hi := cur + target*(1+chunkSearchWindow)
+		cut := ideal
+		bestScore := math.Inf(1)
+		for _, s := range silences {
+			end := s.End
+			if end < 0 {
+				end = duration
+			}
+			mid := (s.Start + end) / 2
+			if mid <= lo || mid >= hi {
+				continue
+			}
+			// Длинная пауза надёжнее короткой, поэтому слегка её поощряем
+			score := math.Abs(mid-ideal) - (end - s.Start)
+			if score < bestScore {
+				bestScore = score
+				cut = mid
+			}
+		}
+		chunks = append(chunks, ChunkBoundary{Start: cur, End: cut})
+		cur = cut
+	}
+	if duration > cur {
+		chunks = append(chunks, ChunkBoundary{Start: cur, End: duration})
+	}
+	return chunks
+}
+
+// transcribeSegment транскрибирует кусок [start, end) файла и возвращает
+// реплики с временем относительно начала куска.
+func (a *App) transcribeSegment(filePath, modelPath string, start, end float64, opts TranscribeOptions) ([]SubtitleCue, error) {
+	out, err := a.runWhisper(filePath, modelPath, start, end, opts)
+	if err != nil {
+		return nil, err
+	}
+	return out.cues(opts.Diarize, opts.WordTimestamps), nil
+}
+
+// GenerateSubtitlesSmart транскрибирует файл кусками, разрезанными по паузам
+// (см. PlanChunks), параллельно в workers потоков, и возвращает общий SRT.
+// Прогресс отправляется во фронтенд событием "transcriptionProgress".
+// forceRefresh — как в GenerateSubtitles.
+func (a *App) GenerateSubtitlesSmart(filePath string, lang string, modelName string, targetSeconds int, workers int, forceRefresh bool) (_ string, err error) {
+	log.Printf("[GenerateSubtitlesSmart] Генерация субтитров: файл=%s, язык=%s, модель=%s, цель=%d сек\n", filePath, lang, modelName, targetSeconds)
+	if lang == "" {
+		lang = "ru"
+	}
+	if workers <= 0 {
+		workers = defaultChunkWorkers
+	}
+	opts := TranscribeOptions{Lang: lang, Model: modelName, ForceRefresh: forceRefresh}
+	run := a.startHistory("smart", filePath, opts)
+	run.ChunkSeconds, run.Workers = targetSeconds, workers
+	defer func() { a.finishHistory(run, err) }()
+
+	modelPath, err := a.resolveModelPath(modelName)
+	if err != nil {
+		log.Printf("[GenerateSubtitlesSmart] %v\n", err)
+		return "", err
+	}
+
+	chunks, err := a.PlanChunks(filePath, targetSeconds)
+	if err != nil {
+		return "", err
+	}
+
+	results := make([][]SubtitleCue, len(chunks))
+	errs := make([]error, len(chunks))
+	jobs := make(chan int)
+	var mu sync.Mutex
+	done := 0
+	// После первой ошибки оставшиеся куски не распознаются: результат всё
+	// равно будет отброшен
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if failed.Load() {
+					continue
+				}
+				results[i], errs[i] = a.transcribeSegment(filePath, modelPath, chunks[i].Start, chunks[i].End, opts)
+				if errs[i] != nil {
+					failed.Store(true)
+					continue
+				}
+				shiftCues(results[i], int64(math.Round(chunks[i].Start*1000)))
+				mu.Lock()
+				done++
+				a.emitTranscriptionProgress(filePath, done, len(chunks))
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range chunks {
+		if failed.Load() {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var cues []SubtitleCue
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("[GenerateSubtitlesSmart] Ошибка куска %d (%.1f-%.1f): %v\n", i, chunks[i].Start, chunks[i].End, err)
+			return "", err
+		}
+		cues = append(cues, results[i]...)
+	}
+	sort.SliceStable(cues, func(i, j int) bool { return cues[i].Start < cues[j].Start })
+	renumberCues(cues)
+	log.Printf("[GenerateSubtitlesSmart] Субтитры успешно сгенерированы: %d реплик\n", len(cues))
+	run.Cues = len(cues)
+	return formatSRT(cues), nil
+}
+
+// emitTranscriptionProgress сообщает фронтенду о числе готовых кусков
+func (a *App) emitTranscriptionProgress(filePath string, done, total int) {
+	if a.ctx == nil {
+		return
+	}
+	runtime.EventsEmit(a.ctx, "transcriptionProgress", map[string]interface{}{
+		"file":    filePath,
+		"done":    done,
+		"total":   total,
+		"percent": done * 100 / total,
+	})
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// runBenchmarkCLI запускает RunBenchmark из командной строки и возвращает код выхода
+func runBenchmarkCLI(args []string) int {
+	fs := flag.NewFlagSet("benchmark", flag.ContinueOnError)
+	models := fs.String("models", "", "модели через запятую, например small,large-v3-turbo")
+	corpus := fs.String("corpus", "", "каталог с медиафайлами и эталонами (.txt, .srt, .vtt, .ass) с тем же именем")
+	lang := fs.String("lang", "ru", "язык распознавания")
+	out := fs.String("out", "benchmark", "путь отчёта без расширения")
+	formats := fs.String("formats", "json,csv,md", "форматы отчёта")
+	keepCase := fs.Bool("keep-case", false, "учитывать регистр")
+	keepPunct := fs.Bool("keep-punct", false, "учитывать знаки препинания")
+	keepYo := fs.Bool("keep-yo", false, "не приравнивать ё к е")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *models == "" || *corpus == "" {
+		fmt.Fprintln(os.Stderr, "usage: submagic benchmark -models small,base -corpus DIR [-lang ru] [-out benchmark]")
+		fs.PrintDefaults()
+		return 2
+	}
+
+	norm := defaultTextNormalization
+	norm.Lowercase = !*keepCase
+	norm.StripPunctuation = !*keepPunct
+	norm.FoldYo = !*keepYo
+
+	app := NewApp()
+	app.initModelsDir()
+	report, err := app.RunBenchmark(BenchmarkConfig{
+		Models:        splitList(*models),
+		CorpusDir:     *corpus,
+		Lang:          *lang,
+		Normalization: norm,
+		ReportPath:    *out,
+		Formats:       splitList(*formats),
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		return 1
+	}
+	for _, s := range report.Summary {
+		fmt.Printf("%-22s WER %6.2f%%  CER %6.2f%%  RTF %.2f  ошибок: %d\n", s.Model, s.WER*100, s.CER*100, s.RTF, s.Failed)
+	}
+	for _, f := range report.ReportFiles {
+		fmt.Println("Отчёт:", f)
+	}
+	return 0
+}
+
+// splitList разбирает список через запятую, отбрасывая пробелы и пустые элементы
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
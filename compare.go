@@ -0,0 +1,275 @@
+package main
+
+import (
+	"log"
+	"sort"
+	"strings"
+)
+
+// WordDiff фрагмент пословного сравнения: equal — слово есть в обеих
+// версиях, delete — только в A, insert — только в B
+type WordDiff struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// CueComparison группа реплик A и B, пересекающихся по времени. Обычно это
+// пара реплик, но при разбиении или слиянии с одной стороны их несколько.
+type CueComparison struct {
+	A      []int  `json:"a"` // номера реплик (Index) в A
+	B      []int  `json:"b"`
+	Status string `json:"status"` // same, retimed, changed, onlyA, onlyB
+	AText  string `json:"aText"`
+	BText  string `json:"bText"`
+	// StartDelta и EndDelta сдвиг границ группы в B относительно A, мс
+	StartDelta int64      `json:"startDelta"`
+	EndDelta   int64      `json:"endDelta"`
+	Diff       []WordDiff `json:"diff"`
+}
+
+// CompareSummary итоговая статистика сравнения
+type CompareSummary struct {
+	CuesA    int `json:"cuesA"`
+	CuesB    int `json:"cuesB"`
+	Same     int `json:"same"`
+	Retimed  int `json:"retimed"`
+	Changed  int `json:"changed"`
+	OnlyA    int `json:"onlyA"`
+	OnlyB    int `json:"onlyB"`
+	Regroups int `json:"regroups"` // групп, где реплики разбиты или слиты
+	WordsA   int `json:"wordsA"`
+	WordsB   int `json:"wordsB"`
+	Equal    int `json:"equal"`
+	Deleted  int `json:"deleted"`
+	Inserted int `json:"inserted"`
+	// WordErrorRate число правок (замена, вставка, удаление) на слово A;
+	// A считается эталоном
+	WordErrorRate float64 `json:"wordErrorRate"`
+	// Similarity доля общих слов: 2·equal / (wordsA + wordsB)
+	Similarity float64 `json:"similarity"`
+	// Сдвиги начала реплик в сопоставленных группах, мс
+	MeanAbsStartDelta   int64 `json:"meanAbsStartDelta"`
+	MedianAbsStartDelta int64 `json:"medianAbsStartDelta"`
+	MaxAbsStartDelta    int64 `json:"maxAbsStartDelta"`
+}
+
+// SubtitleComparison результат CompareSubtitles
+type SubtitleComparison struct {
+	Groups  []CueComparison `json:"groups"`
+	Summary CompareSummary  `json:"summary"`
+}
+
+// timingTolerance расхождение границ, которое ещё считается совпадением
+const timingTolerance = 100
+
+// cuesLinked считает реплики одной группой, если они перекрываются хотя бы
+// на треть более короткой из них
+func cuesLinked(a, b SubtitleCue) bool {
+	overlap := min(a.End, b.End) - max(a.Start, b.Start)
+	shorter := min(a.End-a.Start, b.End-b.Start)
+	return overlap > 0 && overlap*3 >= shorter
+}
+
+// groupCuesByTime разбивает реплики A и B на компоненты связности по
+// перекрытию. Реплики каждого документа должны идти по времени.
+func groupCuesByTime(a, b []SubtitleCue) [][2][]int {
+	// Система непересекающихся множеств: 0..len(a)-1 — A, далее — B
+	parent := make([]int, len(a)+len(b))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(x int) int {
+		if parent[x] != x {
+			parent[x] = find(parent[x])
+		}
+		return parent[x]
+	}
+	lo := 0
+	for i, ca := range a {
+		for lo < len(b) && b[lo].End <= ca.Start {
+			lo++
+		}
+		for j := lo; j < len(b) && b[j].Start < ca.End; j++ {
+			if cuesLinked(ca, b[j]) {
+				parent[find(i)] = find(len(a) + j)
+			}
+		}
+	}
+
+	byRoot := map[int]int{}
+	var groups [][2][]int
+	for x := range parent {
+		r := find(x)
+		g, ok := byRoot[r]
+		if !ok {
+			g = len(groups)
+			byRoot[r] = g
+			groups = append(groups, [2][]int{})
+		}
+		if x < len(a) {
+			groups[g][0] = append(groups[g][0], x)
+		} else {
+			groups[g][1] = append(groups[g][1], x-len(a))
+		}
+	}
+	start := func(g [2][]int) int64 {
+		if len(g[0]) > 0 {
+			return a[g[0][0]].Start
+		}
+		return b[g[1][0]].Start
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return start(groups[i]) < start(groups[j]) })
+	return groups
+}
+
+// diffWords сравнивает тексты пословно; знаки препинания и регистр не
+// учитываются, но в результат попадают слова в исходном написании
+func diffWords(aText, bText string) []WordDiff {
+	split := func(s string) ([]string, []string) {
+		var orig, norm []string
+		for _, f := range strings.Fields(s) {
+			if n := strings.ReplaceAll(normalizeCueText(f), " ", ""); n != "" {
+				orig = append(orig, f)
+				norm = append(norm, n)
+			}
+		}
+		return orig, norm
+	}
+	aOrig, aNorm := split(aText)
+	bOrig, bNorm := split(bText)
+	match := alignWords(aNorm, bNorm)
+
+	var diff []WordDiff
+	j := 0
+	for i, m := range match {
+		if m < 0 {
+			diff = append(diff, WordDiff{Op: "delete", Text: aOrig[i]})
+			continue
+		}
+		for ; j < m; j++ {
+			diff = append(diff, WordDiff{Op: "insert", Text: bOrig[j]})
+		}
+		diff = append(diff, WordDiff{Op: "equal", Text: bOrig[m]})
+		j = m + 1
+	}
+	for ; j < len(bOrig); j++ {
+		diff = append(diff, WordDiff{Op: "insert", Text: bOrig[j]})
+	}
+	return diff
+}
+
+// diffEdits считает правки пословного сравнения: подряд идущие удаления и
+// вставки образуют замены
+func diffEdits(diff []WordDiff) int {
+	edits, del, ins := 0, 0, 0
+	flush := func() {
+		edits += max(del, ins)
+		del, ins = 0, 0
+	}
+	for _, d := range diff {
+		switch d.Op {
+		case "delete":
+			del++
+		case "insert":
+			ins++
+		default:
+			flush()
+		}
+	}
+	flush()
+	return edits
+}
+
+func joinCueTexts(cues []SubtitleCue, idx []int) (string, []int) {
+	texts := make([]string, len(idx))
+	numbers := make([]int, len(idx))
+	for k, i := range idx {
+		texts[k] = cues[i].Text
+		numbers[k] = cues[i].Index
+	}
+	return strings.Join(texts, "\n"), numbers
+}
+
+// CompareSubtitles сравнивает две версии субтитров (например, результаты
+// двух моделей или машинный и отредактированный текст): реплики
+// сопоставляются по времени, текст сравнивается пословно. A считается
+// эталоном для WordErrorRate.
+func (a *App) CompareSubtitles(docA SubtitleDocument, docB SubtitleDocument) (*SubtitleComparison, error) {
+	log.Printf("[CompareSubtitles] Сравнение: %d реплик против %d\n", len(docA.Cues), len(docB.Cues))
+	cuesA := append([]SubtitleCue(nil), docA.Cues...)
+	cuesB := append([]SubtitleCue(nil), docB.Cues...)
+	sort.SliceStable(cuesA, func(i, j int) bool { return cuesA[i].Start < cuesA[j].Start })
+	sort.SliceStable(cuesB, func(i, j int) bool { return cuesB[i].Start < cuesB[j].Start })
+
+	result := &SubtitleComparison{Groups: []CueComparison{}}
+	s := &result.Summary
+	s.CuesA, s.CuesB = len(cuesA), len(cuesB)
+	var startDeltas []int64
+	edits := 0
+	for _, g := range groupCuesByTime(cuesA, cuesB) {
+		var c CueComparison
+		c.AText, c.A = joinCueTexts(cuesA, g[0])
+		c.BText, c.B = joinCueTexts(cuesB, g[1])
+		c.Diff = diffWords(c.AText, c.BText)
+		for _, d := range c.Diff {
+			switch d.Op {
+			case "equal":
+				s.Equal++
+			case "delete":
+				s.Deleted++
+			case "insert":
+				s.Inserted++
+			}
+		}
+		edits += diffEdits(c.Diff)
+
+		switch {
+		case len(g[1]) == 0:
+			c.Status = "onlyA"
+			s.OnlyA++
+		case len(g[0]) == 0:
+			c.Status = "onlyB"
+			s.OnlyB++
+		default:
+			c.StartDelta = cuesB[g[1][0]].Start - cuesA[g[0][0]].Start
+			c.EndDelta = cuesB[g[1][len(g[1])-1]].End - cuesA[g[0][len(g[0])-1]].End
+			startDeltas = append(startDeltas, abs64(c.StartDelta))
+			if len(g[0]) > 1 || len(g[1]) > 1 {
+				s.Regroups++
+			}
+			switch {
+			case diffEdits(c.Diff) > 0:
+				c.Status = "changed"
+				s.Changed++
+			case abs64(c.StartDelta) > timingTolerance || abs64(c.EndDelta) > timingTolerance || len(g[0]) != len(g[1]):
+				c.Status = "retimed"
+				s.Retimed++
+			default:
+				c.Status = "same"
+				s.Same++
+			}
+		}
+		result.Groups = append(result.Groups, c)
+	}
+
+	s.WordsA = s.Equal + s.Deleted
+	s.WordsB = s.Equal + s.Inserted
+	if s.WordsA > 0 {
+		s.WordErrorRate = float64(edits) / float64(s.WordsA)
+	}
+	if s.WordsA+s.WordsB > 0 {
+		s.Similarity = 2 * float64(s.Equal) / float64(s.WordsA+s.WordsB)
+	}
+	if len(startDeltas) > 0 {
+		var sum int64
+		for _, d := range startDeltas {
+			sum += d
+			s.MaxAbsStartDelta = max(s.MaxAbsStartDelta, d)
+		}
+		s.MeanAbsStartDelta = sum / int64(len(startDeltas))
+		s.MedianAbsStartDelta = median(startDeltas)
+	}
+	log.Printf("[CompareSubtitles] Групп: %d, изменено: %d, WER: %.3f\n", len(result.Groups), s.Changed, s.WordErrorRate)
+	return result, nil
+}
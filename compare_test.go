@@ -0,0 +1,153 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCompareSubtitles(t *testing.T) {
+	a := NewApp()
+	cue := func(i int, start, end int64, text string) SubtitleCue {
+		return SubtitleCue{Index: i, Start: start, End: end, Text: text}
+	}
+	tests := []struct {
+		name     string
+		a, b     []SubtitleCue
+		statuses []string
+		groups   [][2][]int // номера реплик A и B в каждой группе
+		regroups int
+	}{
+		{"same ignores case and punctuation",
+			[]SubtitleCue{cue(1, 0, 1000, "привет мир")},
+			[]SubtitleCue{cue(1, 50, 1050, "Привет, мир!")},
+			[]string{"same"}, [][2][]int{{{1}, {1}}}, 0},
+		{"retimed",
+			[]SubtitleCue{cue(1, 0, 1000, "привет мир")},
+			[]SubtitleCue{cue(1, 300, 1300, "привет мир")},
+			[]string{"retimed"}, [][2][]int{{{1}, {1}}}, 0},
+		{"changed",
+			[]SubtitleCue{cue(1, 0, 1000, "привет мир")},
+			[]SubtitleCue{cue(1, 0, 1000, "привет всем")},
+			[]string{"changed"}, [][2][]int{{{1}, {1}}}, 0},
+		{"only in one side",
+			[]SubtitleCue{cue(1, 0, 1000, "раз"), cue(2, 2000, 3000, "два")},
+			[]SubtitleCue{cue(1, 2000, 3000, "два"), cue(2, 5000, 6000, "три")},
+			[]string{"onlyA", "same", "onlyB"}, [][2][]int{{{1}, nil}, {{2}, {1}}, {nil, {2}}}, 0},
+		{"split keeps text",
+			[]SubtitleCue{cue(1, 0, 2000, "раз два три четыре")},
+			[]SubtitleCue{cue(1, 0, 1000, "раз два"), cue(2, 1000, 2000, "три четыре")},
+			[]string{"retimed"}, [][2][]int{{{1}, {1, 2}}}, 1},
+		{"merge with edit",
+			[]SubtitleCue{cue(1, 0, 1000, "раз два"), cue(2, 1000, 2000, "три")},
+			[]SubtitleCue{cue(1, 0, 2000, "раз два четыре")},
+			[]string{"changed"}, [][2][]int{{{1, 2}, {1}}}, 1},
+		// Перекрытие меньше трети реплики не связывает их
+		{"small overlap not linked",
+			[]SubtitleCue{cue(1, 0, 1000, "раз")},
+			[]SubtitleCue{cue(1, 900, 3000, "раз")},
+			[]string{"onlyA", "onlyB"}, [][2][]int{{{1}, nil}, {nil, {1}}}, 0},
+		{"unsorted input",
+			[]SubtitleCue{cue(2, 2000, 3000, "два"), cue(1, 0, 1000, "раз")},
+			[]SubtitleCue{cue(1, 0, 1000, "раз"), cue(2, 2000, 3000, "два")},
+			[]string{"same", "same"}, [][2][]int{{{1}, {1}}, {{2}, {2}}}, 0},
+	}
+	for _, tt := range tests {
+		got, err := a.CompareSubtitles(SubtitleDocument{Cues: tt.a}, SubtitleDocument{Cues: tt.b})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(got.Groups) != len(tt.statuses) {
+			t.Errorf("%s: %d groups, want %d: %+v", tt.name, len(got.Groups), len(tt.statuses), got.Groups)
+			continue
+		}
+		for i, g := range got.Groups {
+			if g.Status != tt.statuses[i] {
+				t.Errorf("%s: group %d status %q, want %q", tt.name, i, g.Status, tt.statuses[i])
+			}
+			if !equalInts(g.A, tt.groups[i][0]) || !equalInts(g.B, tt.groups[i][1]) {
+				t.Errorf("%s: group %d = %v/%v, want %v/%v", tt.name, i, g.A, g.B, tt.groups[i][0], tt.groups[i][1])
+			}
+		}
+		if got.Summary.Regroups != tt.regroups {
+			t.Errorf("%s: %d regroups, want %d", tt.name, got.Summary.Regroups, tt.regroups)
+		}
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDiffWords(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		want  string
+		edits int
+	}{
+		// Совпавшие слова берутся в написании B
+		{"привет мир", "Привет, мир!", "=Привет, =мир!", 0},
+		{"раз два три", "раз три", "=раз -два =три", 1},
+		{"раз три", "раз два три", "=раз +два =три", 1},
+		// Удаление и вставка подряд — одна замена
+		{"раз два три", "раз пять три", "=раз -два +пять =три", 1},
+		{"", "раз", "+раз", 1},
+		{"— ...", "", "", 0},
+	}
+	ops := map[string]string{"equal": "=", "delete": "-", "insert": "+"}
+	for _, tt := range tests {
+		diff := diffWords(tt.a, tt.b)
+		parts := make([]string, len(diff))
+		for i, d := range diff {
+			parts[i] = ops[d.Op] + d.Text
+		}
+		if got := strings.Join(parts, " "); got != tt.want {
+			t.Errorf("diffWords(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+		if got := diffEdits(diff); got != tt.edits {
+			t.Errorf("diffEdits(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.edits)
+		}
+	}
+}
+
+func TestCompareSummary(t *testing.T) {
+	a := NewApp()
+	docA := SubtitleDocument{Cues: []SubtitleCue{
+		{Index: 1, Start: 0, End: 1000, Text: "раз два"},
+		{Index: 2, Start: 2000, End: 3000, Text: "три четыре"},
+		{Index: 3, Start: 4000, End: 5000, Text: "пять"},
+	}}
+	docB := SubtitleDocument{Cues: []SubtitleCue{
+		{Index: 1, Start: 0, End: 1000, Text: "раз два"},
+		{Index: 2, Start: 2400, End: 3400, Text: "три шесть"},
+	}}
+	got, err := a.CompareSubtitles(docA, docB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := got.Summary
+	if s.Same != 1 || s.Changed != 1 || s.OnlyA != 1 || s.OnlyB != 0 {
+		t.Errorf("statuses = %+v", s)
+	}
+	if s.WordsA != 5 || s.WordsB != 4 || s.Equal != 3 || s.Deleted != 2 || s.Inserted != 1 {
+		t.Errorf("word counts = %+v", s)
+	}
+	// Правки: замена «четыре» → «шесть» и удаление «пять»
+	if math.Abs(s.WordErrorRate-2.0/5) > 1e-9 {
+		t.Errorf("WER = %v, want 0.4", s.WordErrorRate)
+	}
+	if math.Abs(s.Similarity-6.0/9) > 1e-9 {
+		t.Errorf("similarity = %v, want %v", s.Similarity, 6.0/9)
+	}
+	if s.MeanAbsStartDelta != 200 || s.MaxAbsStartDelta != 400 {
+		t.Errorf("start deltas mean %d max %d, want 200 and 400", s.MeanAbsStartDelta, s.MaxAbsStartDelta)
+	}
+}
@@ -0,0 +1,439 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// editDocumentVersion версия формата файла, который пишет SaveDocument
+const editDocumentVersion = 1
+
+// maxUndoDepth сколько последних правок можно отменить
+const maxUndoDepth = 500
+
+// cueSplice правка документа: начиная с позиции At реплики Old заменяются на New.
+// Любая операция редактора (вставка, удаление, разделение, склейка, правка
+// текста и времени) выражается через неё, поэтому отмена — обратная замена,
+// а история без труда сохраняется на диск.
+type cueSplice struct {
+	Name string        `json:"name"`
+	At   int           `json:"at"`
+	Old  []SubtitleCue `json:"old"`
+	New  []SubtitleCue `json:"new"`
+	// Gen порядковый номер правки в сессии; по нему определяется, сохранён ли документ
+	Gen int64 `json:"-"`
+}
+
+func (s cueSplice) apply(cues []SubtitleCue) []SubtitleCue {
+	return spliceCues(cues, s.At, len(s.Old), s.New)
+}
+
+func (s cueSplice) revert(cues []SubtitleCue) []SubtitleCue {
+	return spliceCues(cues, s.At, len(s.New), s.Old)
+}
+
+func spliceCues(cues []SubtitleCue, at, remove int, insert []SubtitleCue) []SubtitleCue {
+	result := make([]SubtitleCue, 0, len(cues)-remove+len(insert))
+	result = append(result, cues[:at]...)
+	result = append(result, insert...)
+	result = append(result, cues[at+remove:]...)
+	renumberCues(result)
+	return result
+}
+
+// editSession документ, открытый для редактирования, с историей правок
+type editSession struct {
+	doc  SubtitleDocument
+	undo []cueSplice
+	redo []cueSplice
+	// gen — номер последней правки; base — номер правки, вытесненной со дна
+	// стека отмены (0 — исходный документ); saved — номер правки на вершине
+	// стека отмены на момент последнего сохранения
+	gen   int64
+	base  int64
+	saved int64
+	path  string
+}
+
+// newEditSession создаёт сессию из документа и сохранённой истории. История
+// проверяется на согласованность с документом: испорченная отбрасывается,
+// чтобы отмена не вышла за границы списка реплик.
+func newEditSession(doc SubtitleDocument, undo, redo []cueSplice, path string) *editSession {
+	if !validHistory(len(doc.Cues), undo, redo) {
+		log.Printf("[newEditSession] История правок не соответствует документу и отброшена\n")
+		undo, redo = nil, nil
+	}
+	s := &editSession{doc: doc, path: path}
+	s.undo = append([]cueSplice(nil), undo...)
+	s.redo = append([]cueSplice(nil), redo...)
+	for i := range s.undo {
+		s.gen++
+		s.undo[i].Gen = s.gen
+	}
+	for i := len(s.redo) - 1; i >= 0; i-- {
+		s.gen++
+		s.redo[i].Gen = s.gen
+	}
+	s.saved = s.head()
+	return s
+}
+
+// validHistory проверяет, что правки стека отмены можно откатить, а стека
+// повтора — применить, начиная с документа из n реплик
+func validHistory(n int, undo, redo []cueSplice) bool {
+	fits := func(n int, sp cueSplice, remove int) bool {
+		return sp.At >= 0 && remove >= 0 && sp.At+remove <= n
+	}
+	cur := n
+	for i := len(undo) - 1; i >= 0; i-- {
+		if !fits(cur, undo[i], len(undo[i].New)) {
+			return false
+		}
+		cur += len(undo[i].Old) - len(undo[i].New)
+	}
+	cur = n
+	for i := len(redo) - 1; i >= 0; i-- {
+		if !fits(cur, redo[i], len(redo[i].Old)) {
+			return false
+		}
+		cur += len(redo[i].New) - len(redo[i].Old)
+	}
+	return true
+}
+
+// head номер правки, которой соответствует текущее состояние документа
+func (s *editSession) head() int64 {
+	if len(s.undo) == 0 {
+		return s.base
+	}
+	return s.undo[len(s.undo)-1].Gen
+}
+
+// EditSessionState состояние сессии редактирования для фронтенда
+type EditSessionState struct {
+	ID       string            `json:"id"`
+	Document *SubtitleDocument `json:"document"`
+	Path     string            `json:"path"`
+	Dirty    bool              `json:"dirty"`
+	CanUndo  bool              `json:"canUndo"`
+	CanRedo  bool              `json:"canRedo"`
+	UndoName string            `json:"undoName,omitempty"`
+	RedoName string            `json:"redoName,omitempty"`
+}
+
+// savedDocument формат файла документа вместе с историей правок
+type savedDocument struct {
+	Version  int              `json:"version"`
+	Document SubtitleDocument `json:"document"`
+	Undo     []cueSplice      `json:"undo,omitempty"`
+	Redo     []cueSplice      `json:"redo,omitempty"`
+}
+
+func (s *editSession) state(id string) *EditSessionState {
+	doc := s.doc
+	doc.Cues = append([]SubtitleCue(nil), s.doc.Cues...)
+	st := &EditSessionState{
+		ID:       id,
+		Document: &doc,
+		Path:     s.path,
+		Dirty:    s.saved != s.head(),
+		CanUndo:  len(s.undo) > 0,
+		CanRedo:  len(s.redo) > 0,
+	}
+	if st.CanUndo {
+		st.UndoName = s.undo[len(s.undo)-1].Name
+	}
+	if st.CanRedo {
+		st.RedoName = s.redo[len(s.redo)-1].Name
+	}
+	return st
+}
+
+// do применяет правку и кладёт её в стек отмены, очищая стек повтора
+func (s *editSession) do(sp cueSplice) {
+	s.gen++
+	sp.Gen = s.gen
+	s.doc.Cues = sp.apply(s.doc.Cues)
+	s.undo = append(s.undo, sp)
+	s.redo = nil
+	if len(s.undo) > maxUndoDepth {
+		s.base = s.undo[0].Gen
+		s.undo = s.undo[1:]
+	}
+}
+
+// position переводит номер реплики (1..N) в позицию в срезе
+func (s *editSession) position(index int) (int, error) {
+	if index < 1 || index > len(s.doc.Cues) {
+		return 0, fmt.Errorf("cue %d not found", index)
+	}
+	return index - 1, nil
+}
+
+// withSession выполняет fn над сессией id под блокировкой и возвращает новое состояние
+func (a *App) withSession(id string, fn func(s *editSession) error) (*EditSessionState, error) {
+	a.sessionsMu.Lock()
+	defer a.sessionsMu.Unlock()
+	s, ok := a.sessions[id]
+	if !ok {
+		return nil, errors.New("edit session not found")
+	}
+	if err := fn(s); err != nil {
+		return nil, err
+	}
+	return s.state(id), nil
+}
+
+func (a *App) addSession(s *editSession) *EditSessionState {
+	a.sessionsMu.Lock()
+	defer a.sessionsMu.Unlock()
+	if a.sessions == nil {
+		a.sessions = map[string]*editSession{}
+	}
+	a.sessionSeq++
+	id := fmt.Sprintf("doc-%d", a.sessionSeq)
+	a.sessions[id] = s
+	return s.state(id)
+}
+
+// OpenEditSession открывает документ для редактирования
+func (a *App) OpenEditSession(doc SubtitleDocument) *EditSessionState {
+	log.Printf("[OpenEditSession] Новая сессия: %d реплик\n", len(doc.Cues))
+	doc.Cues = append([]SubtitleCue(nil), doc.Cues...)
+	renumberCues(doc.Cues)
+	return a.addSession(&editSession{doc: doc})
+}
+
+// CloseEditSession закрывает сессию; несохранённые правки теряются
+func (a *App) CloseEditSession(id string) {
+	log.Printf("[CloseEditSession] %s\n", id)
+	a.sessionsMu.Lock()
+	defer a.sessionsMu.Unlock()
+	delete(a.sessions, id)
+}
+
+// GetEditSession возвращает текущее состояние сессии
+func (a *App) GetEditSession(id string) (*EditSessionState, error) {
+	return a.withSession(id, func(s *editSession) error { return nil })
+}
+
+// InsertCue вставляет реплику так, чтобы она получила номер index
+func (a *App) InsertCue(id string, index int, cue SubtitleCue) (*EditSessionState, error) {
+	return a.withSession(id, func(s *editSession) error {
+		if index < 1 || index > len(s.doc.Cues)+1 {
+			return fmt.Errorf("invalid position %d", index)
+		}
+		if cue.End < cue.Start {
+			return errors.New("cue ends before it starts")
+		}
+		s.do(cueSplice{Name: "insert", At: index - 1, New: []SubtitleCue{cue}})
+		return nil
+	})
+}
+
+// DeleteCue удаляет реплику
+func (a *App) DeleteCue(id string, index int) (*EditSessionState, error) {
+	return a.withSession(id, func(s *editSession) error {
+		at, err := s.position(index)
+		if err != nil {
+			return err
+		}
+		s.do(cueSplice{Name: "delete", At: at, Old: []SubtitleCue{s.doc.Cues[at]}})
+		return nil
+	})
+}
+
+// EditCueText заменяет текст реплики. Пословные метки сбрасываются, так как
+// больше не соответствуют тексту.
+func (a *App) EditCueText(id string, index int, text string) (*EditSessionState, error) {
+	return a.withSession(id, func(s *editSession) error {
+		at, err := s.position(index)
+		if err != nil {
+			return err
+		}
+		old := s.doc.Cues[at]
+		c := old
+		c.Text = text
+		c.Words = nil
+		s.do(cueSplice{Name: "text", At: at, Old: []SubtitleCue{old}, New: []SubtitleCue{c}})
+		return nil
+	})
+}
+
+// EditCueTiming задаёт реплике новые начало и конец (мс)
+func (a *App) EditCueTiming(id string, index int, start int64, end int64) (*EditSessionState, error) {
+	return a.withSession(id, func(s *editSession) error {
+		at, err := s.position(index)
+		if err != nil {
+			return err
+		}
+		if start < 0 || end < start {
+			return errors.New("invalid timing")
+		}
+		old := s.doc.Cues[at]
+		c := old
+		c.Start, c.End = start, end
+		s.do(cueSplice{Name: "timing", At: at, Old: []SubtitleCue{old}, New: []SubtitleCue{c}})
+		return nil
+	})
+}
+
+// SplitCue делит реплику в момент at (мс). Текст делится по пословным меткам,
+// а без них — по границе слова пропорционально времени. Разрез, после
+// которого одна из частей осталась бы без текста, отклоняется.
+func (a *App) SplitCue(id string, index int, at int64) (*EditSessionState, error) {
+	return a.withSession(id, func(s *editSession) error {
+		pos, err := s.position(index)
+		if err != nil {
+			return err
+		}
+		old := s.doc.Cues[pos]
+		if at <= old.Start || at >= old.End {
+			return errors.New("split point is outside the cue")
+		}
+		first, second := splitCueAt(old, at)
+		if strings.TrimSpace(first.Text) == "" || strings.TrimSpace(second.Text) == "" {
+			return errors.New("split point leaves an empty cue")
+		}
+		s.do(cueSplice{Name: "split", At: pos, Old: []SubtitleCue{old}, New: []SubtitleCue{first, second}})
+		return nil
+	})
+}
+
+func splitCueAt(c SubtitleCue, at int64) (SubtitleCue, SubtitleCue) {
+	first, second := c, c
+	first.End, second.Start = at, at
+	first.Words, second.Words = nil, nil
+	if len(c.Words) > 0 {
+		var a, b []string
+		for _, w := range c.Words {
+			if (w.Start+w.End)/2 < at {
+				first.Words = append(first.Words, w)
+				a = append(a, w.Text)
+			} else {
+				second.Words = append(second.Words, w)
+				b = append(b, w.Text)
+			}
+		}
+		first.Text, second.Text = strings.Join(a, " "), strings.Join(b, " ")
+		return first, second
+	}
+	words := strings.Fields(c.Text)
+	target := int(float64(utf8.RuneCountInString(c.Text)) * float64(at-c.Start) / float64(c.End-c.Start))
+	cut, chars := len(words), 0
+	for i, w := range words {
+		if chars >= target {
+			cut = i
+			break
+		}
+		chars += utf8.RuneCountInString(w) + 1
+	}
+	first.Text, second.Text = strings.Join(words[:cut], " "), strings.Join(words[cut:], " ")
+	return first, second
+}
+
+// MergeCues склеивает реплику index со следующей
+func (a *App) MergeCues(id string, index int) (*EditSessionState, error) {
+	return a.withSession(id, func(s *editSession) error {
+		at, err := s.position(index)
+		if err != nil {
+			return err
+		}
+		if at+1 >= len(s.doc.Cues) {
+			return errors.New("no next cue to merge with")
+		}
+		x, y := s.doc.Cues[at], s.doc.Cues[at+1]
+		merged := x
+		merged.End = max(x.End, y.End)
+		merged.Text = strings.TrimSpace(x.Text + " " + y.Text)
+		merged.Words = append(append([]Word(nil), x.Words...), y.Words...)
+		merged.Review = x.Review || y.Review
+		if y.Confidence > 0 && (x.Confidence == 0 || y.Confidence < x.Confidence) {
+			merged.Confidence = y.Confidence
+		}
+		s.do(cueSplice{Name: "merge", At: at, Old: []SubtitleCue{x, y}, New: []SubtitleCue{merged}})
+		return nil
+	})
+}
+
+// Undo отменяет последнюю правку
+func (a *App) Undo(id string) (*EditSessionState, error) {
+	return a.withSession(id, func(s *editSession) error {
+		if len(s.undo) == 0 {
+			return errors.New("nothing to undo")
+		}
+		sp := s.undo[len(s.undo)-1]
+		s.undo = s.undo[:len(s.undo)-1]
+		s.doc.Cues = sp.revert(s.doc.Cues)
+		s.redo = append(s.redo, sp)
+		return nil
+	})
+}
+
+// Redo повторяет отменённую правку
+func (a *App) Redo(id string) (*EditSessionState, error) {
+	return a.withSession(id, func(s *editSession) error {
+		if len(s.redo) == 0 {
+			return errors.New("nothing to redo")
+		}
+		sp := s.redo[len(s.redo)-1]
+		s.redo = s.redo[:len(s.redo)-1]
+		s.doc.Cues = sp.apply(s.doc.Cues)
+		s.undo = append(s.undo, sp)
+		return nil
+	})
+}
+
+// SaveDocument сохраняет документ вместе с историей правок в path
+// (пустой path — туда же, откуда документ был загружен или сохранён)
+func (a *App) SaveDocument(id string, path string) (*EditSessionState, error) {
+	log.Printf("[SaveDocument] Сохранение %s в %s\n", id, path)
+	return a.withSession(id, func(s *editSession) error {
+		if path == "" {
+			path = s.path
+		}
+		if path == "" {
+			return errors.New("no path to save document")
+		}
+		data, err := json.MarshalIndent(savedDocument{
+			Version:  editDocumentVersion,
+			Document: s.doc,
+			Undo:     s.undo,
+			Redo:     s.redo,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			log.Printf("[SaveDocument] Ошибка записи: %v\n", err)
+			return err
+		}
+		s.path = path
+		s.saved = s.head()
+		return nil
+	})
+}
+
+// LoadDocument открывает сохранённый документ в новой сессии; история правок
+// восстанавливается, так что отмена работает и после перезапуска
+func (a *App) LoadDocument(path string) (*EditSessionState, error) {
+	log.Printf("[LoadDocument] Загрузка %s\n", path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("[LoadDocument] Ошибка чтения: %v\n", err)
+		return nil, err
+	}
+	var saved savedDocument
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("invalid document file: %w", err)
+	}
+	if saved.Version > editDocumentVersion {
+		return nil, fmt.Errorf("document version %d is newer than supported %d", saved.Version, editDocumentVersion)
+	}
+	return a.addSession(newEditSession(saved.Document, saved.Undo, saved.Redo, path)), nil
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func editorDoc() SubtitleDocument {
+	return SubtitleDocument{Cues: []SubtitleCue{
+		{Index: 1, Start: 0, End: 2000, Text: "один два три четыре"},
+		{Index: 2, Start: 3000, End: 4000, Text: "пять"},
+	}}
+}
+
+func cueTexts(st *EditSessionState) string {
+	texts := make([]string, len(st.Document.Cues))
+	for i, c := range st.Document.Cues {
+		texts[i] = c.Text
+	}
+	return strings.Join(texts, "|")
+}
+
+func TestValidHistory(t *testing.T) {
+	cue := SubtitleCue{Text: "x"}
+	tests := []struct {
+		name       string
+		n          int
+		undo, redo []cueSplice
+		want       bool
+	}{
+		{"empty", 2, nil, nil, true},
+		{"insert at end", 3, []cueSplice{{At: 2, New: []SubtitleCue{cue}}}, nil, true},
+		{"insert past end", 2, []cueSplice{{At: 2, New: []SubtitleCue{cue}}}, nil, false},
+		{"negative position", 2, []cueSplice{{At: -1, Old: []SubtitleCue{cue}}}, nil, false},
+		{"chain", 1, []cueSplice{
+			{At: 0, New: []SubtitleCue{cue, cue}},
+			{At: 1, Old: []SubtitleCue{cue}},
+		}, nil, true},
+		{"redo delete", 2, nil, []cueSplice{{At: 1, Old: []SubtitleCue{cue}}}, true},
+		{"redo delete missing cue", 1, nil, []cueSplice{{At: 1, Old: []SubtitleCue{cue}}}, false},
+	}
+	for _, tt := range tests {
+		if got := validHistory(tt.n, tt.undo, tt.redo); got != tt.want {
+			t.Errorf("%s: validHistory = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEditSessionUndoRedo(t *testing.T) {
+	a := NewApp()
+	st := a.OpenEditSession(editorDoc())
+	id := st.ID
+	steps := []struct {
+		name string
+		run  func() (*EditSessionState, error)
+		want string
+	}{
+		{"insert", func() (*EditSessionState, error) {
+			return a.InsertCue(id, 2, SubtitleCue{Start: 2500, End: 2800, Text: "вставка"})
+		}, "один два три четыре|вставка|пять"},
+		{"edit text", func() (*EditSessionState, error) { return a.EditCueText(id, 3, "шесть") }, "один два три четыре|вставка|шесть"},
+		{"delete", func() (*EditSessionState, error) { return a.DeleteCue(id, 2) }, "один два три четыре|шесть"},
+		{"split", func() (*EditSessionState, error) { return a.SplitCue(id, 1, 1000) }, "один два|три четыре|шесть"},
+		{"merge", func() (*EditSessionState, error) { return a.MergeCues(id, 2) }, "один два|три четыре шесть"},
+		{"undo merge", func() (*EditSessionState, error) { return a.Undo(id) }, "один два|три четыре|шесть"},
+		{"undo split", func() (*EditSessionState, error) { return a.Undo(id) }, "один два три четыре|шесть"},
+		{"redo split", func() (*EditSessionState, error) { return a.Redo(id) }, "один два|три четыре|шесть"},
+	}
+	for _, step := range steps {
+		st, err := step.run()
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got := cueTexts(st); got != step.want {
+			t.Fatalf("%s: cues = %q, want %q", step.name, got, step.want)
+		}
+		for i, c := range st.Document.Cues {
+			if c.Index != i+1 {
+				t.Fatalf("%s: cue %d has index %d", step.name, i, c.Index)
+			}
+		}
+	}
+	// Новая правка очищает стек повтора
+	st, err := a.EditCueTiming(id, 1, 0, 900)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.CanRedo {
+		t.Errorf("redo stack survived a new edit")
+	}
+	if _, err := a.Redo(id); err == nil {
+		t.Errorf("redo after a new edit succeeded")
+	}
+	for i := 0; i < 5; i++ {
+		if st, err = a.Undo(id); err != nil {
+			t.Fatalf("undo %d: %v", i, err)
+		}
+	}
+	if got, want := cueTexts(st), "один два три четыре|пять"; got != want || st.CanUndo {
+		t.Errorf("after undoing everything cues = %q, canUndo %v", got, st.CanUndo)
+	}
+}
+
+func TestSplitCueRejectsEmptyHalf(t *testing.T) {
+	a := NewApp()
+	doc := SubtitleDocument{Cues: []SubtitleCue{
+		{Index: 1, Start: 0, End: 2000, Text: "один два три четыре"},
+		{Index: 2, Start: 3000, End: 4000, Text: "слово"},
+		{Index: 3, Start: 5000, End: 6000, Text: "раз два", Words: []Word{{Text: "раз", Start: 5000, End: 5400}, {Text: "два", Start: 5400, End: 6000}}},
+	}}
+	id := a.OpenEditSession(doc).ID
+	tests := []struct {
+		name  string
+		index int
+		at    int64
+		ok    bool
+	}{
+		{"outside before", 1, 0, false},
+		{"outside after", 1, 2000, false},
+		{"near start", 1, 10, false},
+		{"near end", 1, 1990, false},
+		{"single word", 2, 3500, false},
+		{"before all words", 3, 5100, false},
+		{"between words", 3, 5400, true},
+	}
+	for _, tt := range tests {
+		_, err := a.SplitCue(id, tt.index, tt.at)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: SplitCue(%d, %d) error = %v, want ok %v", tt.name, tt.index, tt.at, err, tt.ok)
+		}
+	}
+}
+
+func TestEditSessionDirtyAndReload(t *testing.T) {
+	a := NewApp()
+	path := filepath.Join(t.TempDir(), "doc.json")
+	id := a.OpenEditSession(editorDoc()).ID
+
+	st, _ := a.EditCueText(id, 2, "шесть")
+	if !st.Dirty {
+		t.Errorf("edit did not mark the document dirty")
+	}
+	if st, _ = a.SaveDocument(id, path); st.Dirty {
+		t.Errorf("saved document is dirty")
+	}
+	if st, _ = a.Undo(id); !st.Dirty {
+		t.Errorf("undo after save is not dirty")
+	}
+	if st, _ = a.Redo(id); st.Dirty {
+		t.Errorf("redo back to the saved state is dirty")
+	}
+	// Та же по содержанию, но другая правка — документ всё равно изменён
+	a.Undo(id)
+	if st, _ = a.EditCueText(id, 2, "шесть"); !st.Dirty {
+		t.Errorf("new edit equal to the saved one is not dirty")
+	}
+
+	a.SaveDocument(id, path)
+	loaded, err := a.LoadDocument(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Dirty || !loaded.CanUndo {
+		t.Errorf("loaded session dirty %v, canUndo %v", loaded.Dirty, loaded.CanUndo)
+	}
+	st, err = a.Undo(loaded.ID)
+	if err != nil || cueTexts(st) != "один два три четыре|пять" || !st.Dirty {
+		t.Errorf("undo after load: %v %q dirty %v", err, cueTexts(st), st.Dirty)
+	}
+
+	// История, не подходящая к документу, отбрасывается
+	data, _ := os.ReadFile(path)
+	var saved savedDocument
+	json.Unmarshal(data, &saved)
+	saved.Undo[0].At = 10
+	data, _ = json.Marshal(saved)
+	os.WriteFile(path, data, 0644)
+	if loaded, err = a.LoadDocument(path); err != nil || loaded.CanUndo || loaded.CanRedo {
+		t.Errorf("corrupt history kept: err %v, canUndo %v", err, loaded.CanUndo)
+	}
+}
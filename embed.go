@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// EmbedTrack дорожка субтитров для вшивания в контейнер: либо готовый
+// файл Path, либо документ Document, который будет сохранён в Format
+type EmbedTrack struct {
+	Path     string            `json:"path,omitempty"`
+	Document *SubtitleDocument `json:"document,omitempty"`
+	Format   string            `json:"format,omitempty"` // srt (по умолчанию) или ass
+	Language string            `json:"language"`         // ISO 639-1 или 639-2
+	Title    string            `json:"title,omitempty"`
+	Default  bool              `json:"default"`
+	Forced   bool              `json:"forced"`
+}
+
+// iso6392 переводит двухбуквенные коды языков в трёхбуквенные, которые
+// требуются в метаданных MP4 и рекомендуются для MKV
+var iso6392 = map[string]string{
+	"ru": "rus", "en": "eng", "uk": "ukr", "be": "bel", "kk": "kaz", "de": "ger",
+	"fr": "fre", "es": "spa", "it": "ita", "pt": "por", "pl": "pol", "tr": "tur",
+	"ja": "jpn", "zh": "chi", "ko": "kor", "ar": "ara", "hi": "hin", "nl": "dut",
+}
+
+func languageTag(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if code, ok := iso6392[lang]; ok {
+		return code
+	}
+	if len(lang) == 3 {
+		return lang
+	}
+	return "und"
+}
+
+// subtitleCodecFor выбирает кодек дорожки для контейнера: MP4 поддерживает
+// только mov_text, WebM — только WebVTT, MKV хранит SRT, ASS и WebVTT как есть
+func subtitleCodecFor(container, trackPath string) (string, error) {
+	switch container {
+	case ".mp4", ".m4v", ".mov":
+		return "mov_text", nil
+	case ".webm":
+		return "webvtt", nil
+	case ".mkv", ".mka":
+		switch strings.ToLower(filepath.Ext(trackPath)) {
+		case ".ass", ".ssa":
+			return "ass", nil
+		case ".vtt":
+			return "webvtt", nil
+		default:
+			return "srt", nil
+		}
+	}
+	return "", fmt.Errorf("unsupported container %q", container)
+}
+
+// EmbedSubtitles вшивает дорожки субтитров в контейнер outPath как отключаемые
+// (soft) субтитры. Видео и аудио копируются без перекодирования. Прогресс
+// отправляется событием "embedProgress".
+func (a *App) EmbedSubtitles(videoPath string, tracks []EmbedTrack, outPath string) (string, error) {
+	log.Printf("[EmbedSubtitles] Вшивание %d дорожек: %s -> %s\n", len(tracks), videoPath, outPath)
+	if len(tracks) == 0 {
+		return "", errors.New("no subtitle tracks")
+	}
+	newDefault := false
+	for _, t := range tracks {
+		if t.Default && newDefault {
+			return "", errors.New("only one track can be default")
+		}
+		newDefault = newDefault || t.Default
+	}
+	if filepath.Clean(outPath) == filepath.Clean(videoPath) {
+		return "", errors.New("output path matches the source file")
+	}
+	if _, err := os.Stat(outPath); err == nil {
+		return "", errors.New("Файл уже существует: " + outPath)
+	}
+	container := strings.ToLower(filepath.Ext(outPath))
+
+	tmpDir, err := os.MkdirTemp("", "submagic_embed_")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	args := []string{"-i", videoPath}
+	paths := make([]string, len(tracks))
+	for i, t := range tracks {
+		paths[i] = t.Path
+		if t.Document != nil {
+			format := t.Format
+			if format == "" {
+				format = "srt"
+			}
+			content, err := a.ExportSubtitles(*t.Document, format)
+			if err != nil {
+				return "", err
+			}
+			paths[i] = filepath.Join(tmpDir, fmt.Sprintf("track%d.%s", i, strings.SplitN(format, "-", 2)[0]))
+			if err := os.WriteFile(paths[i], []byte(content), 0644); err != nil {
+				return "", err
+			}
+		}
+		if paths[i] == "" {
+			return "", fmt.Errorf("track %d has neither path nor document", i+1)
+		}
+		args = append(args, "-i", paths[i])
+	}
+
+	// Имеющиеся дорожки исходника сохраняются. MKV принимает всё как есть;
+	// в MP4 и WebM не бывает вложений и графических субтитров, а текстовые
+	// перекодируются в mov_text и WebVTT.
+	existing, err := a.ListEmbeddedSubtitles(videoPath)
+	if err != nil {
+		return "", err
+	}
+	mp4 := container == ".mp4" || container == ".m4v" || container == ".mov"
+	webm := container == ".webm"
+	var kept []EmbeddedSubtitleTrack
+	if mp4 || webm {
+		args = append(args, "-map", "0:v?", "-map", "0:a?")
+		for _, t := range existing {
+			if !t.Text {
+				log.Printf("[EmbedSubtitles] Графическая дорожка %d (%s) не поддерживается в %s и будет пропущена\n", t.Index, t.Codec, container)
+				continue
+			}
+			args = append(args, "-map", fmt.Sprintf("0:%d", t.Index))
+			kept = append(kept, t)
+		}
+	} else {
+		args = append(args, "-map", "0")
+		kept = existing
+	}
+	for i := range tracks {
+		args = append(args, "-map", fmt.Sprintf("%d:0", i+1))
+	}
+	args = append(args, "-c", "copy")
+	for i, t := range kept {
+		// mov_text бывает только в MP4, в MKV он переводится в SRT
+		switch {
+		case mp4 && t.Codec != "mov_text":
+			args = append(args, fmt.Sprintf("-c:s:%d", i), "mov_text")
+		case webm && t.Codec != "webvtt":
+			args = append(args, fmt.Sprintf("-c:s:%d", i), "webvtt")
+		case !mp4 && !webm && t.Codec == "mov_text":
+			args = append(args, fmt.Sprintf("-c:s:%d", i), "srt")
+		}
+		// Дорожкой по умолчанию остаётся только новая
+		if newDefault && t.Default {
+			disposition := "0"
+			if t.Forced {
+				disposition = "forced"
+			}
+			args = append(args, fmt.Sprintf("-disposition:s:%d", i), disposition)
+		}
+	}
+	for i, t := range tracks {
+		codec, err := subtitleCodecFor(container, paths[i])
+		if err != nil {
+			return "", err
+		}
+		// Новые дорожки идут после сохранённых
+		n := len(kept) + i
+		args = append(args, fmt.Sprintf("-c:s:%d", n), codec,
+			fmt.Sprintf("-metadata:s:s:%d", n), "language="+languageTag(t.Language))
+		if t.Title != "" {
+			args = append(args, fmt.Sprintf("-metadata:s:s:%d", n), "title="+t.Title)
+		}
+		var disposition []string
+		if t.Default {
+			disposition = append(disposition, "default")
+		}
+		if t.Forced {
+			disposition = append(disposition, "forced")
+		}
+		if len(disposition) == 0 {
+			disposition = append(disposition, "0")
+		}
+		args = append(args, fmt.Sprintf("-disposition:s:%d", n), strings.Join(disposition, "+"))
+	}
+	args = append(args, "-n", outPath)
+
+	duration, err := probeMediaDuration(videoPath)
+	if err != nil {
+		log.Printf("[EmbedSubtitles] Длительность неизвестна, прогресс не будет показан: %v\n", err)
+	}
+	err = runFFmpegProgress(context.Background(), args, duration, func(percent float64) {
+		if a.ctx != nil {
+			runtime.EventsEmit(a.ctx, "embedProgress", map[string]interface{}{
+				"file":    outPath,
+				"percent": int(percent),
+			})
+		}
+	})
+	if err != nil {
+		log.Printf("[EmbedSubtitles] Ошибка ffmpeg: %v\n", err)
+		return "", err
+	}
+	log.Printf("[EmbedSubtitles] Готово: %s\n", outPath)
+	return outPath, nil
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// ExportSubtitles сериализует документ в один из форматов: srt, vtt, ass,
+// а также ass-karaoke и vtt-words с подсветкой слов (нужны пословные метки)
+func (a *App) ExportSubtitles(doc SubtitleDocument, format string) (string, error) {
+	log.Printf("[ExportSubtitles] Экспорт %d реплик в формат %s\n", len(doc.Cues), format)
+	switch strings.ToLower(format) {
+	case "srt":
+		return formatSRT(doc.Cues), nil
+	case "vtt":
+		return formatVTT(&doc), nil
+	case "ass":
+		return formatASS(&doc), nil
+	case "ass-karaoke":
+		return formatKaraokeASS(&doc), nil
+	case "vtt-words":
+		return formatWordsVTT(&doc), nil
+	}
+	return "", errors.New("unknown subtitle format")
+}
+
+// formatVTTTimestamp форматирует миллисекунды в метку WebVTT
+func formatVTTTimestamp(ms int64) string {
+	return strings.Replace(formatSRTTimestamp(ms), ",", ".", 1)
+}
+
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// formatVTT сериализует документ в WebVTT, спикеры выводятся тегом <v>
+func formatVTT(doc *SubtitleDocument) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for _, c := range doc.Cues {
+		fmt.Fprintf(&b, "%s --> %s\n", formatVTTTimestamp(c.Start), formatVTTTimestamp(c.End))
+		text := vttEscaper.Replace(c.Text)
+		if name := doc.speakerName(c); name != "" {
+			text = fmt.Sprintf("<v %s>%s", vttEscaper.Replace(name), text)
+		}
+		b.WriteString(text)
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
+
+// formatASSTimestamp форматирует миллисекунды в метку ASS (H:MM:SS.cc)
+func formatASSTimestamp(ms int64) string {
+	if ms < 0 {
+		ms = 0
+	}
+	cs := ms / 10
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
+
+// Цвета стилей спикеров в формате ASS (&HAABBGGRR)
+var assSpeakerColours = []string{"&H0000FFFF", "&H00FFFF00", "&H0000FF00", "&H00FF00FF", "&H000080FF"}
+
+const assStyleFormat = "Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding"
+
+// assStyle строка раздела [V4+ Styles]. Цвета в формате ASS (&HAABBGGRR).
+type assStyle struct {
+	Name        string
+	FontName    string
+	FontSize    int
+	Primary     string
+	Secondary   string
+	Outline     string
+	Back        string
+	Bold        bool
+	Italic      bool
+	BorderStyle int // 1 — контур с тенью, 3 — непрозрачная подложка
+	OutlineSize float64
+	Shadow      float64
+	Alignment   int // как на цифровой клавиатуре: 2 — снизу по центру
+	MarginL     int
+	MarginR     int
+	MarginV     int
+}
+
+// defaultASSStyle стиль по умолчанию для кадра 1920x1080
+func defaultASSStyle() assStyle {
+	return assStyle{
+		Name: "Default", FontName: "Arial", FontSize: 48,
+		Primary: "&H00FFFFFF", Secondary: "&H000000FF", Outline: "&H00000000", Back: "&H80000000",
+		BorderStyle: 1, OutlineSize: 2, Shadow: 1, Alignment: 2, MarginL: 40, MarginR: 40, MarginV: 40,
+	}
+}
+
+func assBool(v bool) int {
+	if v {
+		return -1
+	}
+	return 0
+}
+
+func (s assStyle) line() string {
+	return fmt.Sprintf("Style: %s,%s,%d,%s,%s,%s,%s,%d,%d,0,0,100,100,0,0,%d,%g,%g,%d,%d,%d,%d,1",
+		s.Name, s.FontName, s.FontSize, s.Primary, s.Secondary, s.Outline, s.Back,
+		assBool(s.Bold), assBool(s.Italic), s.BorderStyle, s.OutlineSize, s.Shadow,
+		s.Alignment, s.MarginL, s.MarginR, s.MarginV)
+}
+
+// assEscapeText переводит текст реплики в синтаксис ASS
+func assEscapeText(s string) string {
+	s = strings.NewReplacer("{", "(", "}", ")").Replace(s)
+	return strings.ReplaceAll(s, "\n", `\N`)
+}
+
+// formatASS сериализует документ в ASS. Имя спикера попадает в поле Name
+// (актёр), а у каждого спикера свой стиль с отдельным цветом.
+func formatASS(doc *SubtitleDocument) string {
+	return formatASSWith(doc, func(c SubtitleCue) string { return assEscapeText(c.Text) })
+}
+
+// formatASSWith сериализует документ в ASS, получая текст события от
+// dialogueText. Стили импортированного ASS-файла сохраняются.
+func formatASSWith(doc *SubtitleDocument, dialogueText func(SubtitleCue) string) string {
+	if doc.Styles != nil && len(doc.Styles.Styles) > 0 {
+		return formatASSSheet(doc, dialogueText)
+	}
+	return formatASSStyled(doc, defaultASSStyle(), 1920, 1080, dialogueText)
+}
+
+// formatASSStyled сериализует документ в ASS с базовым стилем base для кадра
+// playResX x playResY. Стили спикеров наследуют base и отличаются цветом.
+func formatASSStyled(doc *SubtitleDocument, base assStyle, playResX, playResY int, dialogueText func(SubtitleCue) string) string {
+	var b strings.Builder
+	writeASSHeader(&b, playResX, playResY)
+	b.WriteString("[V4+ Styles]\n" + assStyleFormat + "\n")
+	base.Name = "Default"
+	b.WriteString(base.line() + "\n")
+
+	ids := make([]string, 0, len(doc.Speakers))
+	for id := range doc.Speakers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	styles := map[string]string{}
+	for i, id := range ids {
+		st := base
+		st.Name = "Speaker_" + id
+		st.Primary = assSpeakerColours[i%len(assSpeakerColours)]
+		styles[id] = st.Name
+		b.WriteString(st.line() + "\n")
+	}
+
+	writeASSEvents(&b, doc, func(c SubtitleCue) string {
+		if s, ok := styles[c.Speaker]; ok {
+			return s
+		}
+		return "Default"
+	}, dialogueText)
+	return b.String()
+}
+
+// formatASSSheet сериализует документ со стилями, сохранёнными при импорте
+func formatASSSheet(doc *SubtitleDocument, dialogueText func(SubtitleCue) string) string {
+	sheet := doc.Styles
+	playResX, playResY := sheet.PlayResX, sheet.PlayResY
+	if playResX <= 0 || playResY <= 0 {
+		playResX, playResY = 384, 288 // значения по умолчанию в VSFilter и libass
+	}
+	var b strings.Builder
+	writeASSHeader(&b, playResX, playResY)
+	b.WriteString("[V4+ Styles]\n" + sheet.Format + "\n")
+	for _, line := range sheet.Styles {
+		b.WriteString(line + "\n")
+	}
+	names := sheet.names()
+	fallback := "Default"
+	if !names[fallback] {
+		fallback = strings.TrimSpace(strings.SplitN(strings.TrimPrefix(sheet.Styles[0], "Style:"), ",", 2)[0])
+	}
+	writeASSEvents(&b, doc, func(c SubtitleCue) string {
+		if names[c.Style] {
+			return c.Style
+		}
+		return fallback
+	}, dialogueText)
+	return b.String()
+}
+
+func writeASSHeader(b *strings.Builder, playResX, playResY int) {
+	fmt.Fprintf(b, "[Script Info]\nScriptType: v4.00+\nPlayResX: %d\nPlayResY: %d\nWrapStyle: 0\nScaledBorderAndShadow: yes\n\n", playResX, playResY)
+}
+
+// writeASSEvents пишет раздел [Events]; styleOf выбирает стиль реплики
+func writeASSEvents(b *strings.Builder, doc *SubtitleDocument, styleOf, dialogueText func(SubtitleCue) string) {
+	b.WriteString("\n[Events]\nFormat: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n")
+	for _, c := range doc.Cues {
+		name := strings.ReplaceAll(doc.speakerName(c), ",", " ")
+		fmt.Fprintf(b, "Dialogue: 0,%s,%s,%s,%s,0,0,0,,%s\n",
+			formatASSTimestamp(c.Start), formatASSTimestamp(c.End), styleOf(c), name, dialogueText(c))
+	}
+}
+
+// formatKaraokeASS сериализует документ в ASS с тегами \k: каждое слово
+// подсвечивается в момент произнесения. Реплики без пословных меток
+// выводятся обычным текстом.
+func formatKaraokeASS(doc *SubtitleDocument) string {
+	return formatASSWith(doc, func(c SubtitleCue) string {
+		if len(c.Words) == 0 {
+			return assEscapeText(c.Text)
+		}
+		var b strings.Builder
+		prev := c.Start
+		for i, w := range c.Words {
+			// Паузу перед словом заполняем пустым слогом, чтобы подсветка не убегала вперёд
+			if gap := (w.Start - prev) / 10; gap > 0 {
+				fmt.Fprintf(&b, `{\k%d}`, gap)
+			}
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			fmt.Fprintf(&b, `{\k%d}%s`, max((w.End-max(w.Start, prev))/10, 1), assEscapeText(w.Text))
+			prev = max(w.End, prev)
+		}
+		return b.String()
+	})
+}
+
+// formatWordsVTT сериализует документ в WebVTT со встроенными метками времени
+// перед каждым словом; плееры подсвечивают произнесённые слова через ::cue(:past)
+func formatWordsVTT(doc *SubtitleDocument) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for _, c := range doc.Cues {
+		fmt.Fprintf(&b, "%s --> %s\n", formatVTTTimestamp(c.Start), formatVTTTimestamp(c.End))
+		if name := doc.speakerName(c); name != "" {
+			fmt.Fprintf(&b, "<v %s>", vttEscaper.Replace(name))
+		}
+		if len(c.Words) == 0 {
+			b.WriteString(vttEscaper.Replace(c.Text))
+		}
+		for i, w := range c.Words {
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			// Метка должна лежать строго внутри реплики
+			if w.Start > c.Start && w.Start < c.End {
+				fmt.Fprintf(&b, "<%s>", formatVTTTimestamp(w.Start))
+			}
+			fmt.Fprintf(&b, "<c>%s</c>", vttEscaper.Replace(w.Text))
+		}
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+)
+
+// EmbeddedSubtitleTrack дорожка субтитров внутри медиафайла
+type EmbeddedSubtitleTrack struct {
+	Index    int    `json:"index"` // номер потока в файле (как в ffprobe)
+	Codec    string `json:"codec"`
+	Language string `json:"language,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Default  bool   `json:"default"`
+	Forced   bool   `json:"forced"`
+	// Text дорожка текстовая и её можно импортировать; графические
+	// (PGS, VobSub, DVB) требуют распознавания и не поддерживаются
+	Text bool `json:"text"`
+}
+
+// textSubtitleCodecs текстовые кодеки, которые ffmpeg умеет перевести в SRT
+var textSubtitleCodecs = map[string]bool{
+	"subrip": true, "srt": true, "ass": true, "ssa": true, "webvtt": true,
+	"mov_text": true, "text": true, "microdvd": true, "subviewer": true,
+}
+
+// languageCode переводит трёхбуквенный код языка из метаданных в двухбуквенный
+func languageCode(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	for short, long := range iso6392 {
+		if long == tag {
+			return short
+		}
+	}
+	// Библиографические и терминологические коды ISO 639-2 иногда расходятся
+	switch tag {
+	case "deu":
+		return "de"
+	case "fra":
+		return "fr"
+	case "zho":
+		return "zh"
+	case "nld":
+		return "nl"
+	case "und", "":
+		return ""
+	}
+	return tag
+}
+
+type ffprobeStreams struct {
+	Streams []struct {
+		Index       int               `json:"index"`
+		CodecName   string            `json:"codec_name"`
+		Tags        map[string]string `json:"tags"`
+		Disposition map[string]int    `json:"disposition"`
+	} `json:"streams"`
+}
+
+// ListEmbeddedSubtitles возвращает дорожки субтитров медиафайла
+func (a *App) ListEmbeddedSubtitles(path string) ([]EmbeddedSubtitleTrack, error) {
+	log.Printf("[ListEmbeddedSubtitles] Файл: %s\n", path)
+	out, err := exec.Command(ffprobePath, "-v", "error", "-select_streams", "s",
+		"-show_entries", "stream=index,codec_name:stream_tags=language,title:stream_disposition=default,forced",
+		"-of", "json", path).Output()
+	if err != nil {
+		log.Printf("[ListEmbeddedSubtitles] Ошибка ffprobe: %v\n", err)
+		return nil, fmt.Errorf("ffprobe error: %v", err)
+	}
+	var probe ffprobeStreams
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return nil, fmt.Errorf("ffprobe: cannot parse streams: %v", err)
+	}
+	tracks := make([]EmbeddedSubtitleTrack, 0, len(probe.Streams))
+	for _, s := range probe.Streams {
+		tracks = append(tracks, EmbeddedSubtitleTrack{
+			Index:    s.Index,
+			Codec:    s.CodecName,
+			Language: languageCode(s.Tags["language"]),
+			Title:    s.Tags["title"],
+			Default:  s.Disposition["default"] == 1,
+			Forced:   s.Disposition["forced"] == 1,
+			Text:     textSubtitleCodecs[s.CodecName],
+		})
+	}
+	log.Printf("[ListEmbeddedSubtitles] Найдено дорожек: %d\n", len(tracks))
+	return tracks, nil
+}
+
+// ExtractSubtitleTrack извлекает текстовую дорожку с номером потока index в
+// документ субтитров. У дорожек ASS сохраняются стили.
+func (a *App) ExtractSubtitleTrack(path string, index int) (*SubtitleDocument, error) {
+	log.Printf("[ExtractSubtitleTrack] Файл: %s, поток: %d\n", path, index)
+	tracks, err := a.ListEmbeddedSubtitles(path)
+	if err != nil {
+		return nil, err
+	}
+	var track *EmbeddedSubtitleTrack
+	for i := range tracks {
+		if tracks[i].Index == index {
+			track = &tracks[i]
+		}
+	}
+	if track == nil {
+		return nil, fmt.Errorf("stream %d is not a subtitle track", index)
+	}
+	if !track.Text {
+		return nil, fmt.Errorf("subtitle codec %s is image-based and cannot be imported", track.Codec)
+	}
+
+	// ASS извлекается как есть, чтобы сохранить стили; остальное — через SRT
+	format := "srt"
+	if track.Codec == "ass" || track.Codec == "ssa" {
+		format = "ass"
+	}
+	cmd := exec.Command(ffmpegPath, "-v", "error", "-i", path,
+		"-map", fmt.Sprintf("0:%d", index), "-c:s", format, "-f", format, "-")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		log.Printf("[ExtractSubtitleTrack] Ошибка ffmpeg: %v\n", err)
+		return nil, fmt.Errorf("ffmpeg error: %v, out: %s", err, lastLines(stderr.String(), 10))
+	}
+	im := &subtitleImporter{}
+	if format == "ass" {
+		im.parseASS(string(out))
+	} else {
+		im.parseBlocks(string(out), "srt")
+	}
+	for _, issue := range im.issues {
+		log.Printf("[ExtractSubtitleTrack] Строка %d: %s\n", issue.Line, issue.Message)
+	}
+	im.doc.Language = track.Language
+	im.doc.Cues = im.cues
+	log.Printf("[ExtractSubtitleTrack] Извлечено реплик: %d\n", len(im.cues))
+	return &im.doc, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// runFFmpegProgress запускает ffmpeg с -progress и вызывает onProgress с
+// процентом готовности (0..100), вычисленным по длительности входа duration
+// (в секундах). Отмена ctx останавливает процесс.
+func runFFmpegProgress(ctx context.Context, args []string, duration float64, onProgress func(percent float64)) error {
+	return runFFmpegProgressIn(ctx, "", args, duration, onProgress)
+}
+
+// runFFmpegProgressIn то же, что runFFmpegProgress, но с рабочим каталогом dir
+func runFFmpegProgressIn(ctx context.Context, dir string, args []string, duration float64, onProgress func(percent float64)) error {
+	full := append([]string{"-hide_banner", "-nostats", "-progress", "pipe:1"}, args...)
+	cmd := exec.CommandContext(ctx, ffmpegPath, full...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("ffmpeg error: %v", err)
+	}
+	parseFFmpegProgress(stdout, duration, onProgress)
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("ffmpeg error: %v, out: %s", err, lastLines(stderr.String(), 10))
+	}
+	onProgress(100)
+	return nil
+}
+
+// parseFFmpegProgress разбирает блоки key=value, которые ffmpeg пишет в -progress
+func parseFFmpegProgress(r io.Reader, duration float64, onProgress func(percent float64)) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || duration <= 0 {
+			continue
+		}
+		// out_time_us и out_time_ms — оба в микросекундах (историческая ошибка ffmpeg)
+		if key == "out_time_us" || key == "out_time_ms" {
+			us, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || us < 0 {
+				continue
+			}
+			onProgress(min(float64(us)/1e6/duration*100, 99.9))
+		}
+	}
+}
+
+// lastLines возвращает последние n строк текста (хвост лога ffmpeg)
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
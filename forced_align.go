@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"strings"
+)
+
+// Предельная длина реплики при сборке из выровненного текста
+const (
+	alignMaxCueChars    = 84
+	alignMaxCueDuration = 7000
+)
+
+// UnmatchedSpan участок эталонного текста, не найденный в распознанной речи;
+// время таких слов оценено по соседним совпадениям
+type UnmatchedSpan struct {
+	Text  string `json:"text"`
+	Start int64  `json:"start"`
+	End   int64  `json:"end"`
+}
+
+// AlignmentResult результат принудительного выравнивания
+type AlignmentResult struct {
+	Document  *SubtitleDocument `json:"document"`
+	Unmatched []UnmatchedSpan   `json:"unmatched"`
+	Matched   int               `json:"matched"` // сколько слов эталона найдено
+	Total     int               `json:"total"`
+}
+
+// AlignTranscript расставляет время для готового текста transcript по аудио
+// filePath: whisper распознаёт речь с пословными метками, слова эталона
+// сопоставляются с распознанными, и реплики собираются из текста эталона
+// с временем распознанных слов
+func (a *App) AlignTranscript(filePath string, transcript string, opts TranscribeOptions) (*AlignmentResult, error) {
+	log.Printf("[AlignTranscript] Выравнивание текста: файл=%s, символов=%d\n", filePath, len(transcript))
+	refWords := strings.Fields(transcript)
+	if len(refWords) == 0 {
+		return nil, errors.New("transcript is empty")
+	}
+	opts.WordTimestamps = true
+	opts.Translate = false
+	recognized, err := a.Transcribe(filePath, opts)
+	if err != nil {
+		return nil, err
+	}
+	hyp := recognizedWords(recognized)
+	if len(hyp) == 0 {
+		return nil, errors.New("Речь в файле не распознана")
+	}
+
+	refNorm := make([]string, len(refWords))
+	for i, w := range refWords {
+		refNorm[i] = strings.ReplaceAll(normalizeCueText(w), " ", "")
+	}
+	match := alignWords(refNorm, normalizedTexts(hyp))
+
+	words := make([]Word, len(refWords))
+	matched := 0
+	for i, j := range match {
+		words[i].Text = refWords[i]
+		if j >= 0 {
+			words[i].Start, words[i].End = hyp[j].Start, hyp[j].End
+			words[i].Confidence = hyp[j].Confidence
+			matched++
+		}
+	}
+	result := &AlignmentResult{Matched: matched, Total: len(refWords)}
+	result.Unmatched = estimateUnmatched(words, match, hyp[len(hyp)-1].End)
+
+	result.Document = &SubtitleDocument{
+		Language: recognized.Language,
+		Model:    recognized.Model,
+		Cues:     groupWordsIntoCues(words, alignMaxCueChars, alignMaxCueDuration),
+	}
+	log.Printf("[AlignTranscript] Найдено слов %d из %d, несовпавших участков %d\n", matched, len(refWords), len(result.Unmatched))
+	return result, nil
+}
+
+// estimateUnmatched распределяет несовпавшие слова равномерно между
+// соседними совпавшими и возвращает такие участки
+func estimateUnmatched(words []Word, match []int, mediaEnd int64) []UnmatchedSpan {
+	var spans []UnmatchedSpan
+	for i := 0; i < len(words); {
+		if match[i] >= 0 {
+			i++
+			continue
+		}
+		j := i
+		for j < len(words) && match[j] < 0 {
+			j++
+		}
+		from := int64(0)
+		if i > 0 {
+			from = words[i-1].End
+		}
+		to := mediaEnd
+		if j < len(words) {
+			to = words[j].Start
+		}
+		to = max(to, from)
+		step := (to - from) / int64(j-i)
+		texts := make([]string, 0, j-i)
+		for k := i; k < j; k++ {
+			words[k].Start = from + step*int64(k-i)
+			words[k].End = words[k].Start + step
+			texts = append(texts, words[k].Text)
+		}
+		spans = append(spans, UnmatchedSpan{Text: strings.Join(texts, " "), Start: from, End: to})
+		i = j
+	}
+	return spans
+}
+
+// groupWordsIntoCues собирает слова в реплики, заканчивая реплику на конце
+// предложения, на длинной паузе или при превышении длины
+func groupWordsIntoCues(words []Word, maxChars int, maxDuration int64) []SubtitleCue {
+	var cues []SubtitleCue
+	var cur []Word
+	chars := 0
+	flush := func() {
+		if len(cur) == 0 {
+			return
+		}
+		texts := make([]string, len(cur))
+		var conf float64
+		for i, w := range cur {
+			texts[i] = w.Text
+			conf += w.Confidence
+		}
+		cues = append(cues, SubtitleCue{
+			Index:      len(cues) + 1,
+			Start:      cur[0].Start,
+			End:        cur[len(cur)-1].End,
+			Text:       strings.Join(texts, " "),
+			Words:      cur,
+			Confidence: conf / float64(len(cur)),
+		})
+		cur, chars = nil, 0
+	}
+	for _, w := range words {
+		if len(cur) > 0 {
+			gap := w.Start - cur[len(cur)-1].End
+			if chars+1+len([]rune(w.Text)) > maxChars || w.End-cur[0].Start > maxDuration || gap > maxMergeGap {
+				flush()
+			}
+		}
+		cur = append(cur, w)
+		chars += len([]rune(w.Text)) + 1
+		if endsSentence(w.Text) {
+			flush()
+		}
+	}
+	flush()
+	return cues
+}
@@ -1,19 +1,170 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {main} from '../models';
 import {context} from '../models';
 
+export function AddHallucinationPhrase(arg1:string,arg2:string):Promise<void>;
+
+export function AddProjectTrack(arg1:main.Project,arg2:string,arg3:main.SubtitleDocument):Promise<main.Project>;
+
+export function AlignTranscript(arg1:string,arg2:string,arg3:main.TranscribeOptions):Promise<main.AlignmentResult>;
+
+export function ApplyLintFixes(arg1:main.SubtitleDocument,arg2:Array<main.LintIssue>):Promise<main.SubtitleDocument>;
+
+export function BurnCaptions(arg1:string,arg2:main.SubtitleDocument,arg3:string,arg4:string):Promise<string>;
+
+export function BurnSubtitles(arg1:string,arg2:main.SubtitleDocument,arg3:main.CaptionStyle,arg4:string):Promise<string>;
+
+export function CancelJob(arg1:string):Promise<boolean>;
+
+export function ClearTranscriptionCache():Promise<void>;
+
+export function CloseEditSession(arg1:string):Promise<void>;
+
+export function CompareSubtitles(arg1:main.SubtitleDocument,arg2:main.SubtitleDocument):Promise<main.SubtitleComparison>;
+
+export function ConvertFramerate(arg1:main.SubtitleDocument,arg2:number,arg3:number):Promise<main.TimingPreview>;
+
+export function DeleteCaptionTemplate(arg1:string):Promise<void>;
+
+export function DeleteCue(arg1:string,arg2:number):Promise<main.EditSessionState>;
+
 export function DeleteModel(arg1:string):Promise<void>;
 
+export function DiscardJob(arg1:string):Promise<void>;
+
 export function DownloadModel(arg1:string):Promise<string>;
 
-export function GenerateSubtitles(arg1:context.Context,arg2:string,arg3:string,arg4:string):Promise<string>;
+export function EditCueText(arg1:string,arg2:number,arg3:string):Promise<main.EditSessionState>;
+
+export function EditCueTiming(arg1:string,arg2:number,arg3:number,arg4:number):Promise<main.EditSessionState>;
+
+export function EmbedSubtitles(arg1:string,arg2:Array<main.EmbedTrack>,arg3:string):Promise<string>;
+
+export function ExportBilingual(arg1:main.BilingualResult,arg2:string):Promise<string>;
+
+export function ExportSubtitles(arg1:main.SubtitleDocument,arg2:string):Promise<string>;
+
+export function ExtractSubtitleTrack(arg1:string,arg2:number):Promise<main.SubtitleDocument>;
 
-export function GenerateSubtitlesChunk(arg1:context.Context,arg2:string,arg3:string,arg4:string,arg5:number,arg6:number):Promise<string>;
+export function FilterHallucinations(arg1:string,arg2:main.SubtitleDocument,arg3:main.HallucinationFilterOptions):Promise<main.HallucinationReport>;
+
+export function FlagLowConfidenceCues(arg1:main.SubtitleDocument,arg2:number):Promise<main.SubtitleDocument>;
+
+export function GenerateSubtitles(arg1:context.Context,arg2:string,arg3:string,arg4:string,arg5:boolean):Promise<string>;
+
+export function GenerateSubtitlesChunk(arg1:context.Context,arg2:string,arg3:string,arg4:string,arg5:number,arg6:number,arg7:boolean):Promise<string>;
+
+export function GenerateSubtitlesSmart(arg1:string,arg2:string,arg3:string,arg4:number,arg5:number,arg6:boolean):Promise<string>;
 
 export function GetActiveModel():Promise<string>;
 
+export function GetCacheStats():Promise<main.CacheStats>;
+
+export function GetEditSession(arg1:string):Promise<main.EditSessionState>;
+
+export function GetHallucinationPhrases(arg1:string):Promise<Array<string>>;
+
+export function GetHistoryEntry(arg1:string):Promise<main.HistoryEntry>;
+
+export function GetLowConfidenceCues(arg1:main.SubtitleDocument,arg2:number):Promise<Array<main.SubtitleCue>>;
+
+export function GetModelStats():Promise<Array<main.ModelStats>>;
+
+export function GetOutputSettings():Promise<main.OutputSettings>;
+
 export function Greet(arg1:string):Promise<string>;
 
+export function ImportCaptionTemplate(arg1:string):Promise<main.CaptionTemplate>;
+
+export function ImportSubtitles(arg1:string):Promise<main.ImportResult>;
+
+export function InsertCue(arg1:string,arg2:number,arg3:main.SubtitleCue):Promise<main.EditSessionState>;
+
+export function LintSubtitles(arg1:main.SubtitleDocument,arg2:main.SubtitleProfile):Promise<Array<main.LintIssue>>;
+
+export function ListCaptionTemplates():Promise<Array<main.CaptionTemplate>>;
+
+export function ListEmbeddedSubtitles(arg1:string):Promise<Array<main.EmbeddedSubtitleTrack>>;
+
+export function ListHistory(arg1:main.HistoryFilter):Promise<Array<main.HistoryEntry>>;
+
+export function ListIncompleteJobs():Promise<Array<main.TranscriptionJob>>;
+
 export function ListModels():Promise<Array<Record<string, any>>>;
 
+export function ListRecentProjects():Promise<Array<main.RecentProject>>;
+
+export function ListSubtitleProfiles():Promise<Array<main.SubtitleProfile>>;
+
+export function LoadDocument(arg1:string):Promise<main.EditSessionState>;
+
+export function MergeCues(arg1:string,arg2:number):Promise<main.EditSessionState>;
+
+export function NewProject(arg1:string,arg2:string):Promise<main.Project>;
+
+export function OpenEditSession(arg1:main.SubtitleDocument):Promise<main.EditSessionState>;
+
+export function OpenProject(arg1:string):Promise<main.Project>;
+
+export function OpenTrackEditSession(arg1:main.Project,arg2:string):Promise<main.EditSessionState>;
+
+export function PlanChunks(arg1:string,arg2:number):Promise<Array<main.ChunkBoundary>>;
+
+export function RecommendModel(arg1:string,arg2:string,arg3:string):Promise<main.ModelRecommendationReport>;
+
+export function Redo(arg1:string):Promise<main.EditSessionState>;
+
+export function ReflowSubtitles(arg1:main.SubtitleDocument,arg2:main.SubtitleProfile):Promise<main.SubtitleDocument>;
+
+export function RelinkProjectMedia(arg1:main.Project,arg2:string):Promise<main.Project>;
+
+export function RemoveHallucinationPhrase(arg1:string,arg2:string):Promise<void>;
+
+export function RenameSpeaker(arg1:main.SubtitleDocument,arg2:string,arg3:string):Promise<main.SubtitleDocument>;
+
+export function RenderCaptions(arg1:main.SubtitleDocument,arg2:string,arg3:number,arg4:number):Promise<string>;
+
+export function RerunHistoryEntry(arg1:string):Promise<main.SubtitleDocument>;
+
+export function ResumeJob(arg1:string):Promise<string>;
+
+export function RunBenchmark(arg1:main.BenchmarkConfig):Promise<main.BenchmarkReport>;
+
+export function SaveCaptionTemplate(arg1:main.CaptionTemplate):Promise<void>;
+
+export function SaveDocument(arg1:string,arg2:string):Promise<main.EditSessionState>;
+
+export function SaveProject(arg1:main.Project,arg2:string):Promise<main.Project>;
+
+export function SaveSubtitles(arg1:string,arg2:main.SubtitleDocument,arg3:string):Promise<string>;
+
 export function SetActiveModel(arg1:string):Promise<void>;
+
+export function SetCacheSettings(arg1:main.CacheSettings):Promise<void>;
+
+export function SetCueReview(arg1:main.SubtitleDocument,arg2:number,arg3:boolean):Promise<main.SubtitleDocument>;
+
+export function SetOutputSettings(arg1:main.OutputSettings):Promise<void>;
+
+export function SetProjectReviewStatus(arg1:main.Project,arg2:string):Promise<main.Project>;
+
+export function ShiftSubtitles(arg1:main.SubtitleDocument,arg2:number,arg3:number,arg4:number):Promise<main.TimingPreview>;
+
+export function SnapToFrames(arg1:main.SubtitleDocument,arg2:number):Promise<main.TimingPreview>;
+
+export function SnapToShotChanges(arg1:string,arg2:main.SubtitleDocument,arg3:number):Promise<main.TimingPreview>;
+
+export function SplitCue(arg1:string,arg2:number,arg3:number):Promise<main.EditSessionState>;
+
+export function StretchSubtitles(arg1:main.SubtitleDocument,arg2:main.SyncPoint,arg3:main.SyncPoint):Promise<main.TimingPreview>;
+
+export function SyncSubtitles(arg1:string,arg2:string,arg3:string):Promise<main.SyncReport>;
+
+export function Transcribe(arg1:string,arg2:main.TranscribeOptions):Promise<main.SubtitleDocument>;
+
+export function TranscribeBilingual(arg1:string,arg2:main.TranscribeOptions):Promise<main.BilingualResult>;
+
+export function Undo(arg1:string):Promise<main.EditSessionState>;
+
+export function UpdateTrackFromSession(arg1:main.Project,arg2:string,arg3:string):Promise<main.Project>;
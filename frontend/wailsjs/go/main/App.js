@@ -2,34 +2,334 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddHallucinationPhrase(arg1, arg2) {
+  return window['go']['main']['App']['AddHallucinationPhrase'](arg1, arg2);
+}
+
+export function AddProjectTrack(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddProjectTrack'](arg1, arg2, arg3);
+}
+
+export function AlignTranscript(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AlignTranscript'](arg1, arg2, arg3);
+}
+
+export function ApplyLintFixes(arg1, arg2) {
+  return window['go']['main']['App']['ApplyLintFixes'](arg1, arg2);
+}
+
+export function BurnCaptions(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['BurnCaptions'](arg1, arg2, arg3, arg4);
+}
+
+export function BurnSubtitles(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['BurnSubtitles'](arg1, arg2, arg3, arg4);
+}
+
+export function CancelJob(arg1) {
+  return window['go']['main']['App']['CancelJob'](arg1);
+}
+
+export function ClearTranscriptionCache() {
+  return window['go']['main']['App']['ClearTranscriptionCache']();
+}
+
+export function CloseEditSession(arg1) {
+  return window['go']['main']['App']['CloseEditSession'](arg1);
+}
+
+export function CompareSubtitles(arg1, arg2) {
+  return window['go']['main']['App']['CompareSubtitles'](arg1, arg2);
+}
+
+export function ConvertFramerate(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ConvertFramerate'](arg1, arg2, arg3);
+}
+
+export function DeleteCaptionTemplate(arg1) {
+  return window['go']['main']['App']['DeleteCaptionTemplate'](arg1);
+}
+
+export function DeleteCue(arg1, arg2) {
+  return window['go']['main']['App']['DeleteCue'](arg1, arg2);
+}
+
 export function DeleteModel(arg1) {
   return window['go']['main']['App']['DeleteModel'](arg1);
 }
 
+export function DiscardJob(arg1) {
+  return window['go']['main']['App']['DiscardJob'](arg1);
+}
+
 export function DownloadModel(arg1) {
   return window['go']['main']['App']['DownloadModel'](arg1);
 }
 
-export function GenerateSubtitles(arg1, arg2, arg3, arg4) {
-  return window['go']['main']['App']['GenerateSubtitles'](arg1, arg2, arg3, arg4);
+export function EditCueText(arg1, arg2, arg3) {
+  return window['go']['main']['App']['EditCueText'](arg1, arg2, arg3);
+}
+
+export function EditCueTiming(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['EditCueTiming'](arg1, arg2, arg3, arg4);
+}
+
+export function EmbedSubtitles(arg1, arg2, arg3) {
+  return window['go']['main']['App']['EmbedSubtitles'](arg1, arg2, arg3);
+}
+
+export function ExportBilingual(arg1, arg2) {
+  return window['go']['main']['App']['ExportBilingual'](arg1, arg2);
+}
+
+export function ExportSubtitles(arg1, arg2) {
+  return window['go']['main']['App']['ExportSubtitles'](arg1, arg2);
+}
+
+export function ExtractSubtitleTrack(arg1, arg2) {
+  return window['go']['main']['App']['ExtractSubtitleTrack'](arg1, arg2);
 }
 
-export function GenerateSubtitlesChunk(arg1, arg2, arg3, arg4, arg5, arg6) {
-  return window['go']['main']['App']['GenerateSubtitlesChunk'](arg1, arg2, arg3, arg4, arg5, arg6);
+export function FilterHallucinations(arg1, arg2, arg3) {
+  return window['go']['main']['App']['FilterHallucinations'](arg1, arg2, arg3);
+}
+
+export function FlagLowConfidenceCues(arg1, arg2) {
+  return window['go']['main']['App']['FlagLowConfidenceCues'](arg1, arg2);
+}
+
+export function GenerateSubtitles(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['GenerateSubtitles'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function GenerateSubtitlesChunk(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
+  return window['go']['main']['App']['GenerateSubtitlesChunk'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
+}
+
+export function GenerateSubtitlesSmart(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['GenerateSubtitlesSmart'](arg1, arg2, arg3, arg4, arg5, arg6);
 }
 
 export function GetActiveModel() {
   return window['go']['main']['App']['GetActiveModel']();
 }
 
+export function GetCacheStats() {
+  return window['go']['main']['App']['GetCacheStats']();
+}
+
+export function GetEditSession(arg1) {
+  return window['go']['main']['App']['GetEditSession'](arg1);
+}
+
+export function GetHallucinationPhrases(arg1) {
+  return window['go']['main']['App']['GetHallucinationPhrases'](arg1);
+}
+
+export function GetHistoryEntry(arg1) {
+  return window['go']['main']['App']['GetHistoryEntry'](arg1);
+}
+
+export function GetLowConfidenceCues(arg1, arg2) {
+  return window['go']['main']['App']['GetLowConfidenceCues'](arg1, arg2);
+}
+
+export function GetModelStats() {
+  return window['go']['main']['App']['GetModelStats']();
+}
+
+export function GetOutputSettings() {
+  return window['go']['main']['App']['GetOutputSettings']();
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function ImportCaptionTemplate(arg1) {
+  return window['go']['main']['App']['ImportCaptionTemplate'](arg1);
+}
+
+export function ImportSubtitles(arg1) {
+  return window['go']['main']['App']['ImportSubtitles'](arg1);
+}
+
+export function InsertCue(arg1, arg2, arg3) {
+  return window['go']['main']['App']['InsertCue'](arg1, arg2, arg3);
+}
+
+export function LintSubtitles(arg1, arg2) {
+  return window['go']['main']['App']['LintSubtitles'](arg1, arg2);
+}
+
+export function ListCaptionTemplates() {
+  return window['go']['main']['App']['ListCaptionTemplates']();
+}
+
+export function ListEmbeddedSubtitles(arg1) {
+  return window['go']['main']['App']['ListEmbeddedSubtitles'](arg1);
+}
+
+export function ListHistory(arg1) {
+  return window['go']['main']['App']['ListHistory'](arg1);
+}
+
+export function ListIncompleteJobs() {
+  return window['go']['main']['App']['ListIncompleteJobs']();
+}
+
 export function ListModels() {
   return window['go']['main']['App']['ListModels']();
 }
 
+export function ListRecentProjects() {
+  return window['go']['main']['App']['ListRecentProjects']();
+}
+
+export function ListSubtitleProfiles() {
+  return window['go']['main']['App']['ListSubtitleProfiles']();
+}
+
+export function LoadDocument(arg1) {
+  return window['go']['main']['App']['LoadDocument'](arg1);
+}
+
+export function MergeCues(arg1, arg2) {
+  return window['go']['main']['App']['MergeCues'](arg1, arg2);
+}
+
+export function NewProject(arg1, arg2) {
+  return window['go']['main']['App']['NewProject'](arg1, arg2);
+}
+
+export function OpenEditSession(arg1) {
+  return window['go']['main']['App']['OpenEditSession'](arg1);
+}
+
+export function OpenProject(arg1) {
+  return window['go']['main']['App']['OpenProject'](arg1);
+}
+
+export function OpenTrackEditSession(arg1, arg2) {
+  return window['go']['main']['App']['OpenTrackEditSession'](arg1, arg2);
+}
+
+export function PlanChunks(arg1, arg2) {
+  return window['go']['main']['App']['PlanChunks'](arg1, arg2);
+}
+
+export function RecommendModel(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RecommendModel'](arg1, arg2, arg3);
+}
+
+export function Redo(arg1) {
+  return window['go']['main']['App']['Redo'](arg1);
+}
+
+export function ReflowSubtitles(arg1, arg2) {
+  return window['go']['main']['App']['ReflowSubtitles'](arg1, arg2);
+}
+
+export function RelinkProjectMedia(arg1, arg2) {
+  return window['go']['main']['App']['RelinkProjectMedia'](arg1, arg2);
+}
+
+export function RemoveHallucinationPhrase(arg1, arg2) {
+  return window['go']['main']['App']['RemoveHallucinationPhrase'](arg1, arg2);
+}
+
+export function RenameSpeaker(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RenameSpeaker'](arg1, arg2, arg3);
+}
+
+export function RenderCaptions(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['RenderCaptions'](arg1, arg2, arg3, arg4);
+}
+
+export function RerunHistoryEntry(arg1) {
+  return window['go']['main']['App']['RerunHistoryEntry'](arg1);
+}
+
+export function ResumeJob(arg1) {
+  return window['go']['main']['App']['ResumeJob'](arg1);
+}
+
+export function RunBenchmark(arg1) {
+  return window['go']['main']['App']['RunBenchmark'](arg1);
+}
+
+export function SaveCaptionTemplate(arg1) {
+  return window['go']['main']['App']['SaveCaptionTemplate'](arg1);
+}
+
+export function SaveDocument(arg1, arg2) {
+  return window['go']['main']['App']['SaveDocument'](arg1, arg2);
+}
+
+export function SaveProject(arg1, arg2) {
+  return window['go']['main']['App']['SaveProject'](arg1, arg2);
+}
+
+export function SaveSubtitles(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SaveSubtitles'](arg1, arg2, arg3);
+}
+
 export function SetActiveModel(arg1) {
   return window['go']['main']['App']['SetActiveModel'](arg1);
 }
+
+export function SetCacheSettings(arg1) {
+  return window['go']['main']['App']['SetCacheSettings'](arg1);
+}
+
+export function SetCueReview(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetCueReview'](arg1, arg2, arg3);
+}
+
+export function SetOutputSettings(arg1) {
+  return window['go']['main']['App']['SetOutputSettings'](arg1);
+}
+
+export function SetProjectReviewStatus(arg1, arg2) {
+  return window['go']['main']['App']['SetProjectReviewStatus'](arg1, arg2);
+}
+
+export function ShiftSubtitles(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ShiftSubtitles'](arg1, arg2, arg3, arg4);
+}
+
+export function SnapToFrames(arg1, arg2) {
+  return window['go']['main']['App']['SnapToFrames'](arg1, arg2);
+}
+
+export function SnapToShotChanges(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SnapToShotChanges'](arg1, arg2, arg3);
+}
+
+export function SplitCue(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SplitCue'](arg1, arg2, arg3);
+}
+
+export function StretchSubtitles(arg1, arg2, arg3) {
+  return window['go']['main']['App']['StretchSubtitles'](arg1, arg2, arg3);
+}
+
+export function SyncSubtitles(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SyncSubtitles'](arg1, arg2, arg3);
+}
+
+export function Transcribe(arg1, arg2) {
+  return window['go']['main']['App']['Transcribe'](arg1, arg2);
+}
+
+export function TranscribeBilingual(arg1, arg2) {
+  return window['go']['main']['App']['TranscribeBilingual'](arg1, arg2);
+}
+
+export function Undo(arg1) {
+  return window['go']['main']['App']['Undo'](arg1);
+}
+
+export function UpdateTrackFromSession(arg1, arg2, arg3) {
+  return window['go']['main']['App']['UpdateTrackFromSession'](arg1, arg2, arg3);
+}
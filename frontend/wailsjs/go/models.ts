@@ -0,0 +1,1586 @@
+export namespace main {
+	
+	export class UnmatchedSpan {
+	    text: string;
+	    start: number;
+	    end: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new UnmatchedSpan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.start = source["start"];
+	        this.end = source["end"];
+	    }
+	}
+	export class StyleSheet {
+	    playResX?: number;
+	    playResY?: number;
+	    format: string;
+	    styles: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new StyleSheet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.playResX = source["playResX"];
+	        this.playResY = source["playResY"];
+	        this.format = source["format"];
+	        this.styles = source["styles"];
+	    }
+	}
+	export class Word {
+	    text: string;
+	    start: number;
+	    end: number;
+	    confidence: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Word(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.start = source["start"];
+	        this.end = source["end"];
+	        this.confidence = source["confidence"];
+	    }
+	}
+	export class SubtitleCue {
+	    index: number;
+	    start: number;
+	    end: number;
+	    text: string;
+	    speaker?: string;
+	    words?: Word[];
+	    confidence?: number;
+	    review?: boolean;
+	    style?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SubtitleCue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.start = source["start"];
+	        this.end = source["end"];
+	        this.text = source["text"];
+	        this.speaker = source["speaker"];
+	        this.words = this.convertValues(source["words"], Word);
+	        this.confidence = source["confidence"];
+	        this.review = source["review"];
+	        this.style = source["style"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SubtitleDocument {
+	    language: string;
+	    model?: string;
+	    cues: SubtitleCue[];
+	    speakers?: Record<string, string>;
+	    styles?: StyleSheet;
+	
+	    static createFrom(source: any = {}) {
+	        return new SubtitleDocument(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.language = source["language"];
+	        this.model = source["model"];
+	        this.cues = this.convertValues(source["cues"], SubtitleCue);
+	        this.speakers = source["speakers"];
+	        this.styles = this.convertValues(source["styles"], StyleSheet);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AlignmentResult {
+	    document?: SubtitleDocument;
+	    unmatched: UnmatchedSpan[];
+	    matched: number;
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AlignmentResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.document = this.convertValues(source["document"], SubtitleDocument);
+	        this.unmatched = this.convertValues(source["unmatched"], UnmatchedSpan);
+	        this.matched = source["matched"];
+	        this.total = source["total"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TextNormalization {
+	    lowercase: boolean;
+	    stripPunctuation: boolean;
+	    foldYo: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TextNormalization(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.lowercase = source["lowercase"];
+	        this.stripPunctuation = source["stripPunctuation"];
+	        this.foldYo = source["foldYo"];
+	    }
+	}
+	export class BenchmarkItem {
+	    media: string;
+	    reference: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BenchmarkItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.media = source["media"];
+	        this.reference = source["reference"];
+	    }
+	}
+	export class BenchmarkConfig {
+	    models: string[];
+	    items?: BenchmarkItem[];
+	    corpusDir?: string;
+	    lang: string;
+	    normalization: TextNormalization;
+	    reportPath?: string;
+	    formats?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BenchmarkConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.models = source["models"];
+	        this.items = this.convertValues(source["items"], BenchmarkItem);
+	        this.corpusDir = source["corpusDir"];
+	        this.lang = source["lang"];
+	        this.normalization = this.convertValues(source["normalization"], TextNormalization);
+	        this.reportPath = source["reportPath"];
+	        this.formats = source["formats"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class BenchmarkModelSummary {
+	    model: string;
+	    files: number;
+	    failed: number;
+	    wer: number;
+	    cer: number;
+	    rtf: number;
+	    peakMemory?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BenchmarkModelSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = source["model"];
+	        this.files = source["files"];
+	        this.failed = source["failed"];
+	        this.wer = source["wer"];
+	        this.cer = source["cer"];
+	        this.rtf = source["rtf"];
+	        this.peakMemory = source["peakMemory"];
+	    }
+	}
+	export class BenchmarkResult {
+	    model: string;
+	    media: string;
+	    refWords: number;
+	    substitutions: number;
+	    deletions: number;
+	    insertions: number;
+	    wer: number;
+	    refChars: number;
+	    charErrors: number;
+	    cer: number;
+	    duration: number;
+	    elapsed: number;
+	    rtf: number;
+	    peakMemory?: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BenchmarkResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = source["model"];
+	        this.media = source["media"];
+	        this.refWords = source["refWords"];
+	        this.substitutions = source["substitutions"];
+	        this.deletions = source["deletions"];
+	        this.insertions = source["insertions"];
+	        this.wer = source["wer"];
+	        this.refChars = source["refChars"];
+	        this.charErrors = source["charErrors"];
+	        this.cer = source["cer"];
+	        this.duration = source["duration"];
+	        this.elapsed = source["elapsed"];
+	        this.rtf = source["rtf"];
+	        this.peakMemory = source["peakMemory"];
+	        this.error = source["error"];
+	    }
+	}
+	export class BenchmarkReport {
+	    // Go type: time
+	    started: any;
+	    lang: string;
+	    normalization: TextNormalization;
+	    summary: BenchmarkModelSummary[];
+	    results: BenchmarkResult[];
+	    reportFiles?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BenchmarkReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.started = this.convertValues(source["started"], null);
+	        this.lang = source["lang"];
+	        this.normalization = this.convertValues(source["normalization"], TextNormalization);
+	        this.summary = this.convertValues(source["summary"], BenchmarkModelSummary);
+	        this.results = this.convertValues(source["results"], BenchmarkResult);
+	        this.reportFiles = source["reportFiles"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class BilingualResult {
+	    original?: SubtitleDocument;
+	    translation?: SubtitleDocument;
+	
+	    static createFrom(source: any = {}) {
+	        return new BilingualResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.original = this.convertValues(source["original"], SubtitleDocument);
+	        this.translation = this.convertValues(source["translation"], SubtitleDocument);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CacheSettings {
+	    disabled: boolean;
+	    maxBytes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.disabled = source["disabled"];
+	        this.maxBytes = source["maxBytes"];
+	    }
+	}
+	export class CacheStats {
+	    entries: number;
+	    bytes: number;
+	    maxBytes: number;
+	    disabled: boolean;
+	    hits: number;
+	    misses: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entries = source["entries"];
+	        this.bytes = source["bytes"];
+	        this.maxBytes = source["maxBytes"];
+	        this.disabled = source["disabled"];
+	        this.hits = source["hits"];
+	        this.misses = source["misses"];
+	    }
+	}
+	export class CaptionStyle {
+	    fontName: string;
+	    fontSize: number;
+	    color: string;
+	    outlineColor: string;
+	    outline: number;
+	    shadow: number;
+	    bold: boolean;
+	    italic: boolean;
+	    position: string;
+	    marginV: number;
+	    marginH: number;
+	    box: boolean;
+	    boxColor: string;
+	    quality: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CaptionStyle(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.fontName = source["fontName"];
+	        this.fontSize = source["fontSize"];
+	        this.color = source["color"];
+	        this.outlineColor = source["outlineColor"];
+	        this.outline = source["outline"];
+	        this.shadow = source["shadow"];
+	        this.bold = source["bold"];
+	        this.italic = source["italic"];
+	        this.position = source["position"];
+	        this.marginV = source["marginV"];
+	        this.marginH = source["marginH"];
+	        this.box = source["box"];
+	        this.boxColor = source["boxColor"];
+	        this.quality = source["quality"];
+	    }
+	}
+	export class CaptionTemplate {
+	    name: string;
+	    description?: string;
+	    style: CaptionStyle;
+	    maxWords: number;
+	    maxChars: number;
+	    animation: string;
+	    highlightColor?: string;
+	    uppercase: boolean;
+	    keywords?: Record<string, string>;
+	    emphasisColor?: string;
+	    builtIn: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CaptionTemplate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.style = this.convertValues(source["style"], CaptionStyle);
+	        this.maxWords = source["maxWords"];
+	        this.maxChars = source["maxChars"];
+	        this.animation = source["animation"];
+	        this.highlightColor = source["highlightColor"];
+	        this.uppercase = source["uppercase"];
+	        this.keywords = source["keywords"];
+	        this.emphasisColor = source["emphasisColor"];
+	        this.builtIn = source["builtIn"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ChunkBoundary {
+	    start: number;
+	    end: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChunkBoundary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start = source["start"];
+	        this.end = source["end"];
+	    }
+	}
+	export class CompareSummary {
+	    cuesA: number;
+	    cuesB: number;
+	    same: number;
+	    retimed: number;
+	    changed: number;
+	    onlyA: number;
+	    onlyB: number;
+	    regroups: number;
+	    wordsA: number;
+	    wordsB: number;
+	    equal: number;
+	    deleted: number;
+	    inserted: number;
+	    wordErrorRate: number;
+	    similarity: number;
+	    meanAbsStartDelta: number;
+	    medianAbsStartDelta: number;
+	    maxAbsStartDelta: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CompareSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.cuesA = source["cuesA"];
+	        this.cuesB = source["cuesB"];
+	        this.same = source["same"];
+	        this.retimed = source["retimed"];
+	        this.changed = source["changed"];
+	        this.onlyA = source["onlyA"];
+	        this.onlyB = source["onlyB"];
+	        this.regroups = source["regroups"];
+	        this.wordsA = source["wordsA"];
+	        this.wordsB = source["wordsB"];
+	        this.equal = source["equal"];
+	        this.deleted = source["deleted"];
+	        this.inserted = source["inserted"];
+	        this.wordErrorRate = source["wordErrorRate"];
+	        this.similarity = source["similarity"];
+	        this.meanAbsStartDelta = source["meanAbsStartDelta"];
+	        this.medianAbsStartDelta = source["medianAbsStartDelta"];
+	        this.maxAbsStartDelta = source["maxAbsStartDelta"];
+	    }
+	}
+	export class WordDiff {
+	    op: string;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WordDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.op = source["op"];
+	        this.text = source["text"];
+	    }
+	}
+	export class CueComparison {
+	    a: number[];
+	    b: number[];
+	    status: string;
+	    aText: string;
+	    bText: string;
+	    startDelta: number;
+	    endDelta: number;
+	    diff: WordDiff[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CueComparison(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.a = source["a"];
+	        this.b = source["b"];
+	        this.status = source["status"];
+	        this.aText = source["aText"];
+	        this.bText = source["bText"];
+	        this.startDelta = source["startDelta"];
+	        this.endDelta = source["endDelta"];
+	        this.diff = this.convertValues(source["diff"], WordDiff);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CueSync {
+	    cueIndex: number;
+	    offset: number;
+	    matched: number;
+	    words: number;
+	    method: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CueSync(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.cueIndex = source["cueIndex"];
+	        this.offset = source["offset"];
+	        this.matched = source["matched"];
+	        this.words = source["words"];
+	        this.method = source["method"];
+	    }
+	}
+	export class DroppedCue {
+	    cue: SubtitleCue;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DroppedCue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.cue = this.convertValues(source["cue"], SubtitleCue);
+	        this.reason = source["reason"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EditSessionState {
+	    id: string;
+	    document?: SubtitleDocument;
+	    path: string;
+	    dirty: boolean;
+	    canUndo: boolean;
+	    canRedo: boolean;
+	    undoName?: string;
+	    redoName?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EditSessionState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.document = this.convertValues(source["document"], SubtitleDocument);
+	        this.path = source["path"];
+	        this.dirty = source["dirty"];
+	        this.canUndo = source["canUndo"];
+	        this.canRedo = source["canRedo"];
+	        this.undoName = source["undoName"];
+	        this.redoName = source["redoName"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EmbedTrack {
+	    path?: string;
+	    document?: SubtitleDocument;
+	    format?: string;
+	    language: string;
+	    title?: string;
+	    default: boolean;
+	    forced: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new EmbedTrack(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.document = this.convertValues(source["document"], SubtitleDocument);
+	        this.format = source["format"];
+	        this.language = source["language"];
+	        this.title = source["title"];
+	        this.default = source["default"];
+	        this.forced = source["forced"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EmbeddedSubtitleTrack {
+	    index: number;
+	    codec: string;
+	    language?: string;
+	    title?: string;
+	    default: boolean;
+	    forced: boolean;
+	    text: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new EmbeddedSubtitleTrack(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.codec = source["codec"];
+	        this.language = source["language"];
+	        this.title = source["title"];
+	        this.default = source["default"];
+	        this.forced = source["forced"];
+	        this.text = source["text"];
+	    }
+	}
+	export class HallucinationFilterOptions {
+	    mode: string;
+	    checkSilence: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new HallucinationFilterOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.checkSilence = source["checkSilence"];
+	    }
+	}
+	export class HallucinationReport {
+	    document?: SubtitleDocument;
+	    dropped: DroppedCue[];
+	
+	    static createFrom(source: any = {}) {
+	        return new HallucinationReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.document = this.convertValues(source["document"], SubtitleDocument);
+	        this.dropped = this.convertValues(source["dropped"], DroppedCue);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TranscribeOptions {
+	    lang: string;
+	    model: string;
+	    diarize: boolean;
+	    translate: boolean;
+	    wordTimestamps: boolean;
+	    hallucinationFilter: string;
+	    forceRefresh?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TranscribeOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.lang = source["lang"];
+	        this.model = source["model"];
+	        this.diarize = source["diarize"];
+	        this.translate = source["translate"];
+	        this.wordTimestamps = source["wordTimestamps"];
+	        this.hallucinationFilter = source["hallucinationFilter"];
+	        this.forceRefresh = source["forceRefresh"];
+	    }
+	}
+	export class HistoryEntry {
+	    id: string;
+	    kind: string;
+	    // Go type: time
+	    started: any;
+	    input: string;
+	    inputHash?: string;
+	    options: TranscribeOptions;
+	    start?: number;
+	    end?: number;
+	    chunkSeconds?: number;
+	    workers?: number;
+	    mediaDuration: number;
+	    wallTime: number;
+	    rtf: number;
+	    success: boolean;
+	    error?: string;
+	    cues: number;
+	    outputs?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.kind = source["kind"];
+	        this.started = this.convertValues(source["started"], null);
+	        this.input = source["input"];
+	        this.inputHash = source["inputHash"];
+	        this.options = this.convertValues(source["options"], TranscribeOptions);
+	        this.start = source["start"];
+	        this.end = source["end"];
+	        this.chunkSeconds = source["chunkSeconds"];
+	        this.workers = source["workers"];
+	        this.mediaDuration = source["mediaDuration"];
+	        this.wallTime = source["wallTime"];
+	        this.rtf = source["rtf"];
+	        this.success = source["success"];
+	        this.error = source["error"];
+	        this.cues = source["cues"];
+	        this.outputs = source["outputs"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HistoryFilter {
+	    model?: string;
+	    input?: string;
+	    status?: string;
+	    limit?: number;
+	    offset?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = source["model"];
+	        this.input = source["input"];
+	        this.status = source["status"];
+	        this.limit = source["limit"];
+	        this.offset = source["offset"];
+	    }
+	}
+	export class ImportIssue {
+	    line: number;
+	    severity: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.severity = source["severity"];
+	        this.message = source["message"];
+	    }
+	}
+	export class ImportResult {
+	    document?: SubtitleDocument;
+	    format: string;
+	    encoding: string;
+	    issues: ImportIssue[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.document = this.convertValues(source["document"], SubtitleDocument);
+	        this.format = source["format"];
+	        this.encoding = source["encoding"];
+	        this.issues = this.convertValues(source["issues"], ImportIssue);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LintFix {
+	    action: string;
+	    end?: number;
+	    text?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LintFix(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.action = source["action"];
+	        this.end = source["end"];
+	        this.text = source["text"];
+	    }
+	}
+	export class LintIssue {
+	    cueIndex: number;
+	    rule: string;
+	    severity: string;
+	    message: string;
+	    fix?: LintFix;
+	
+	    static createFrom(source: any = {}) {
+	        return new LintIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.cueIndex = source["cueIndex"];
+	        this.rule = source["rule"];
+	        this.severity = source["severity"];
+	        this.message = source["message"];
+	        this.fix = this.convertValues(source["fix"], LintFix);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ModelRecommendation {
+	    model: string;
+	    downloaded: boolean;
+	    estimatedSeconds: number;
+	    estimatedMemory: number;
+	    fits: boolean;
+	    score: number;
+	    notes?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelRecommendation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = source["model"];
+	        this.downloaded = source["downloaded"];
+	        this.estimatedSeconds = source["estimatedSeconds"];
+	        this.estimatedMemory = source["estimatedMemory"];
+	        this.fits = source["fits"];
+	        this.score = source["score"];
+	        this.notes = source["notes"];
+	    }
+	}
+	export class SystemInfo {
+	    cpus: number;
+	    threads: number;
+	    availableMemory: number;
+	    freeDisk: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SystemInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.cpus = source["cpus"];
+	        this.threads = source["threads"];
+	        this.availableMemory = source["availableMemory"];
+	        this.freeDisk = source["freeDisk"];
+	    }
+	}
+	export class ModelRecommendationReport {
+	    system: SystemInfo;
+	    duration: number;
+	    priority: string;
+	    recommendations: ModelRecommendation[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelRecommendationReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.system = this.convertValues(source["system"], SystemInfo);
+	        this.duration = source["duration"];
+	        this.priority = source["priority"];
+	        this.recommendations = this.convertValues(source["recommendations"], ModelRecommendation);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ModelStats {
+	    model: string;
+	    runs: number;
+	    failures: number;
+	    mediaDuration: number;
+	    wallTime: number;
+	    rtf: number;
+	    // Go type: time
+	    lastUsed: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = source["model"];
+	        this.runs = source["runs"];
+	        this.failures = source["failures"];
+	        this.mediaDuration = source["mediaDuration"];
+	        this.wallTime = source["wallTime"];
+	        this.rtf = source["rtf"];
+	        this.lastUsed = this.convertValues(source["lastUsed"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class OutputSettings {
+	    directory: string;
+	    filenameTemplate: string;
+	    collision: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OutputSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.directory = source["directory"];
+	        this.filenameTemplate = source["filenameTemplate"];
+	        this.collision = source["collision"];
+	    }
+	}
+	export class SubtitleProfile {
+	    name: string;
+	    maxCharsPerLine: number;
+	    maxLines: number;
+	    minDuration: number;
+	    maxDuration: number;
+	    maxCps: number;
+	    minGap: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SubtitleProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.maxCharsPerLine = source["maxCharsPerLine"];
+	        this.maxLines = source["maxLines"];
+	        this.minDuration = source["minDuration"];
+	        this.maxDuration = source["maxDuration"];
+	        this.maxCps = source["maxCps"];
+	        this.minGap = source["minGap"];
+	    }
+	}
+	export class cueSplice {
+	    name: string;
+	    at: number;
+	    old: SubtitleCue[];
+	    new: SubtitleCue[];
+	
+	    static createFrom(source: any = {}) {
+	        return new cueSplice(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.at = source["at"];
+	        this.old = this.convertValues(source["old"], SubtitleCue);
+	        this.new = this.convertValues(source["new"], SubtitleCue);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProjectTrack {
+	    id: string;
+	    name: string;
+	    language: string;
+	    document: SubtitleDocument;
+	    undo?: cueSplice[];
+	    redo?: cueSplice[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ProjectTrack(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.language = source["language"];
+	        this.document = this.convertValues(source["document"], SubtitleDocument);
+	        this.undo = this.convertValues(source["undo"], cueSplice);
+	        this.redo = this.convertValues(source["redo"], cueSplice);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProjectMedia {
+	    path: string;
+	    relativePath?: string;
+	    size: number;
+	    // Go type: time
+	    modTime: any;
+	    missing?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProjectMedia(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.relativePath = source["relativePath"];
+	        this.size = source["size"];
+	        this.modTime = this.convertValues(source["modTime"], null);
+	        this.missing = source["missing"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Project {
+	    version: number;
+	    name: string;
+	    media: ProjectMedia;
+	    tracks: ProjectTrack[];
+	    transcribe?: TranscribeOptions;
+	    profile?: SubtitleProfile;
+	    reviewStatus: string;
+	    // Go type: time
+	    created: any;
+	    // Go type: time
+	    modified: any;
+	    path?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Project(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.name = source["name"];
+	        this.media = this.convertValues(source["media"], ProjectMedia);
+	        this.tracks = this.convertValues(source["tracks"], ProjectTrack);
+	        this.transcribe = this.convertValues(source["transcribe"], TranscribeOptions);
+	        this.profile = this.convertValues(source["profile"], SubtitleProfile);
+	        this.reviewStatus = source["reviewStatus"];
+	        this.created = this.convertValues(source["created"], null);
+	        this.modified = this.convertValues(source["modified"], null);
+	        this.path = source["path"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class RecentProject {
+	    path: string;
+	    name: string;
+	    exists: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RecentProject(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.name = source["name"];
+	        this.exists = source["exists"];
+	    }
+	}
+	
+	export class SubtitleComparison {
+	    groups: CueComparison[];
+	    summary: CompareSummary;
+	
+	    static createFrom(source: any = {}) {
+	        return new SubtitleComparison(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.groups = this.convertValues(source["groups"], CueComparison);
+	        this.summary = this.convertValues(source["summary"], CompareSummary);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	export class SyncPoint {
+	    from: number;
+	    to: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SyncPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.from = source["from"];
+	        this.to = source["to"];
+	    }
+	}
+	export class SyncReport {
+	    outputPath: string;
+	    document?: SubtitleDocument;
+	    cues: CueSync[];
+	    model: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SyncReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.outputPath = source["outputPath"];
+	        this.document = this.convertValues(source["document"], SubtitleDocument);
+	        this.cues = this.convertValues(source["cues"], CueSync);
+	        this.model = source["model"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class TimingChange {
+	    cueIndex: number;
+	    oldStart: number;
+	    oldEnd: number;
+	    newStart: number;
+	    newEnd: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TimingChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.cueIndex = source["cueIndex"];
+	        this.oldStart = source["oldStart"];
+	        this.oldEnd = source["oldEnd"];
+	        this.newStart = source["newStart"];
+	        this.newEnd = source["newEnd"];
+	    }
+	}
+	export class TimingPreview {
+	    document?: SubtitleDocument;
+	    changes: TimingChange[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TimingPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.document = this.convertValues(source["document"], SubtitleDocument);
+	        this.changes = this.convertValues(source["changes"], TimingChange);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class TranscriptionJob {
+	    id: string;
+	    input: string;
+	    inputHash: string;
+	    options: TranscribeOptions;
+	    chunks: ChunkBoundary[];
+	    // Go type: time
+	    created: any;
+	    completed: number;
+	    error?: string;
+	    pid?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TranscriptionJob(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.input = source["input"];
+	        this.inputHash = source["inputHash"];
+	        this.options = this.convertValues(source["options"], TranscribeOptions);
+	        this.chunks = this.convertValues(source["chunks"], ChunkBoundary);
+	        this.created = this.convertValues(source["created"], null);
+	        this.completed = source["completed"];
+	        this.error = source["error"];
+	        this.pid = source["pid"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+
+}
+
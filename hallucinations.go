@@ -0,0 +1,253 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"strings"
+)
+
+// knownHallucinations фразы, которые whisper выдумывает на тишине и музыке
+// (в основном из титров обучающих субтитров). Сравнение — по normalizeCueText.
+var knownHallucinations = map[string][]string{
+	"ru": {
+		"продолжение следует",
+		"субтитры сделал dimatorzok",
+		"субтитры создавал dimatorzok",
+		"редактор субтитров асемкин корректор аегорова",
+		"субтитры подогнал симон",
+		"спасибо за просмотр",
+		"подписывайтесь на канал",
+	},
+	"en": {
+		"thanks for watching",
+		"thank you for watching",
+		"please subscribe",
+		"subtitles by the amaraorg community",
+	},
+}
+
+// normalizeCueText приводит текст к нижнему регистру без пунктуации для сравнения
+func normalizeCueText(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r == ' ' || r == '\n' || r == '\t':
+			b.WriteRune(' ')
+		case strings.ContainsRune(".,!?…:;\"'()[]«»-—", r):
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// isKnownHallucination проверяет текст по списку фраз для языка
+func isKnownHallucination(text, lang string, phrases map[string][]string) bool {
+	norm := normalizeCueText(text)
+	if norm == "" {
+		return false
+	}
+	for _, p := range phrases[lang] {
+		if norm == normalizeCueText(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Режимы фильтра галлюцинаций
+const (
+	hallucinationRemove = "remove"
+	hallucinationFlag   = "flag"
+)
+
+const (
+	// Тишина, на которой whisper склонен выдумывать текст
+	hallucinationSilenceNoiseDb     = -40.0
+	hallucinationSilenceMinDuration = 2.0
+	// Доля реплики, которая должна приходиться на тишину
+	hallucinationSilenceShare = 0.8
+	// Сколько раз подряд должна повториться фраза внутри реплики
+	minPhraseRepeats = 3
+	// Минимальная длина зацикленного текста в словах: короткие повторы
+	// вроде «да да да» встречаются в обычной речи
+	minLoopWords = 8
+)
+
+// HallucinationFilterOptions настройки фильтра галлюцинаций
+type HallucinationFilterOptions struct {
+	Mode string `json:"mode"` // remove — удалить реплики, flag — пометить для проверки
+	// CheckSilence ищет реплики поверх тишины (требует путь к медиафайлу)
+	CheckSilence bool `json:"checkSilence"`
+}
+
+// DroppedCue реплика, признанная галлюцинацией, с причиной
+type DroppedCue struct {
+	Cue    SubtitleCue `json:"cue"`
+	Reason string      `json:"reason"` // phrase, repeated, loop, silence
+}
+
+// HallucinationReport результат фильтрации
+type HallucinationReport struct {
+	Document *SubtitleDocument `json:"document"`
+	Dropped  []DroppedCue      `json:"dropped"`
+}
+
+// hallucinationPhrases объединяет встроенный список фраз с пользовательским из настроек
+func (a *App) hallucinationPhrases() map[string][]string {
+	result := map[string][]string{}
+	for lang, list := range knownHallucinations {
+		result[lang] = append(result[lang], list...)
+	}
+	if settings, err := a.loadSettings(); err == nil {
+		for lang, list := range settings.HallucinationPhrases {
+			result[lang] = append(result[lang], list...)
+		}
+	}
+	return result
+}
+
+// GetHallucinationPhrases возвращает встроенные и пользовательские фразы для языка
+func (a *App) GetHallucinationPhrases(lang string) []string {
+	return a.hallucinationPhrases()[lang]
+}
+
+// AddHallucinationPhrase добавляет фразу в пользовательский список языка
+func (a *App) AddHallucinationPhrase(lang string, phrase string) error {
+	log.Printf("[AddHallucinationPhrase] %s: %q\n", lang, phrase)
+	if normalizeCueText(phrase) == "" || lang == "" {
+		return errors.New("empty phrase or language")
+	}
+	return a.updateSettings(func(settings *Settings) error {
+		if settings.HallucinationPhrases == nil {
+			settings.HallucinationPhrases = map[string][]string{}
+		}
+		for _, p := range settings.HallucinationPhrases[lang] {
+			if normalizeCueText(p) == normalizeCueText(phrase) {
+				return nil
+			}
+		}
+		settings.HallucinationPhrases[lang] = append(settings.HallucinationPhrases[lang], strings.TrimSpace(phrase))
+		return nil
+	})
+}
+
+// RemoveHallucinationPhrase удаляет фразу из пользовательского списка языка
+func (a *App) RemoveHallucinationPhrase(lang string, phrase string) error {
+	log.Printf("[RemoveHallucinationPhrase] %s: %q\n", lang, phrase)
+	return a.updateSettings(func(settings *Settings) error {
+		list := settings.HallucinationPhrases[lang]
+		kept := list[:0]
+		for _, p := range list {
+			if normalizeCueText(p) != normalizeCueText(phrase) {
+				kept = append(kept, p)
+			}
+		}
+		if len(kept) == len(list) {
+			return errors.New("phrase not found")
+		}
+		settings.HallucinationPhrases[lang] = kept
+		return nil
+	})
+}
+
+// isPhraseLoop определяет зацикливание whisper внутри реплики:
+// весь текст — одна и та же фраза, повторённая minPhraseRepeats раз и более,
+// и в нём не меньше minLoopWords слов
+func isPhraseLoop(text string) bool {
+	words := strings.Fields(normalizeCueText(text))
+	if len(words) < minLoopWords {
+		return false
+	}
+	for unit := 1; unit*minPhraseRepeats <= len(words); unit++ {
+		if len(words)%unit != 0 {
+			continue
+		}
+		loop := true
+		for i := unit; i < len(words) && loop; i++ {
+			loop = words[i] == words[i%unit]
+		}
+		if loop {
+			return true
+		}
+	}
+	return false
+}
+
+// silenceShare возвращает долю реплики, приходящуюся на участки тишины
+func silenceShare(c SubtitleCue, silences []SilenceInterval) float64 {
+	dur := c.End - c.Start
+	if dur <= 0 {
+		return 0
+	}
+	var covered int64
+	for _, s := range silences {
+		start := int64(s.Start * 1000)
+		end := int64(s.End * 1000)
+		if s.End < 0 {
+			end = c.End
+		}
+		if overlap := min(end, c.End) - max(start, c.Start); overlap > 0 {
+			covered += overlap
+		}
+	}
+	return float64(covered) / float64(dur)
+}
+
+// FilterHallucinations находит реплики-галлюцинации: известные фразы, повторы
+// предыдущей реплики, зацикленные фразы и (opts.CheckSilence) текст поверх
+// тишины в filePath. Найденные реплики удаляются или помечаются для проверки.
+func (a *App) FilterHallucinations(filePath string, doc SubtitleDocument, opts HallucinationFilterOptions) (*HallucinationReport, error) {
+	log.Printf("[FilterHallucinations] Фильтр галлюцинаций: файл=%s, реплик=%d, опции=%+v\n", filePath, len(doc.Cues), opts)
+	if opts.Mode == "" {
+		opts.Mode = hallucinationRemove
+	}
+	if opts.Mode != hallucinationRemove && opts.Mode != hallucinationFlag {
+		return nil, errors.New("unknown filter mode")
+	}
+	var silences []SilenceInterval
+	if opts.CheckSilence {
+		var err error
+		silences, err = detectSilences(filePath, hallucinationSilenceNoiseDb, hallucinationSilenceMinDuration)
+		if err != nil {
+			log.Printf("[FilterHallucinations] Ошибка поиска тишины: %v\n", err)
+			return nil, err
+		}
+	}
+
+	phrases := a.hallucinationPhrases()
+	report := &HallucinationReport{}
+	var kept []SubtitleCue
+	prevText := ""
+	for _, c := range doc.Cues {
+		reason := ""
+		norm := normalizeCueText(c.Text)
+		switch {
+		case isKnownHallucination(c.Text, doc.Language, phrases):
+			reason = "phrase"
+		case norm != "" && norm == prevText:
+			reason = "repeated"
+		case isPhraseLoop(c.Text):
+			reason = "loop"
+		case opts.CheckSilence && silenceShare(c, silences) >= hallucinationSilenceShare:
+			reason = "silence"
+		}
+		prevText = norm
+		if reason == "" {
+			kept = append(kept, c)
+			continue
+		}
+		report.Dropped = append(report.Dropped, DroppedCue{Cue: c, Reason: reason})
+		if opts.Mode == hallucinationFlag {
+			c.Review = true
+			kept = append(kept, c)
+		}
+	}
+	if opts.Mode == hallucinationRemove {
+		renumberCues(kept)
+	}
+	doc.Cues = kept
+	report.Document = &doc
+	log.Printf("[FilterHallucinations] Найдено галлюцинаций: %d\n", len(report.Dropped))
+	return report, nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIsPhraseLoop(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"да да да", false},
+		{"No, no, no!", false},
+		{"ha ha ha ha ha ha", false},
+		{"спасибо большое спасибо большое спасибо большое", false},
+		{"да да да да да да да да", true},
+		{"и вот так и вот так и вот так", true},
+		{"Я не знаю. Я не знаю. Я не знаю. Я не знаю.", true},
+		{"и вот так и вот так и вот иначе", false},
+		{"раз два три четыре пять шесть семь восемь", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isPhraseLoop(tt.text); got != tt.want {
+			t.Errorf("isPhraseLoop(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestIsKnownHallucination(t *testing.T) {
+	phrases := map[string][]string{"ru": {"Продолжение следует"}, "en": {"thanks for watching"}}
+	tests := []struct {
+		text, lang string
+		want       bool
+	}{
+		{"Продолжение следует...", "ru", true},
+		{"ПРОДОЛЖЕНИЕ  СЛЕДУЕТ", "ru", true},
+		{"Продолжение следует завтра", "ru", false},
+		{"Thanks for watching!", "en", true},
+		{"Thanks for watching!", "ru", false},
+		{"...", "ru", false},
+	}
+	for _, tt := range tests {
+		if got := isKnownHallucination(tt.text, tt.lang, phrases); got != tt.want {
+			t.Errorf("isKnownHallucination(%q, %s) = %v, want %v", tt.text, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestSilenceShare(t *testing.T) {
+	silences := []SilenceInterval{{Start: 1, End: 2}, {Start: 5, End: -1}}
+	tests := []struct {
+		start, end int64
+		want       float64
+	}{
+		{0, 1000, 0},
+		{1000, 2000, 1},
+		{500, 1500, 0.5},
+		{1500, 2500, 0.5},
+		{6000, 8000, 1}, // тишина до конца файла
+		{4000, 6000, 0.5},
+		{2000, 2000, 0},
+	}
+	for _, tt := range tests {
+		got := silenceShare(SubtitleCue{Start: tt.start, End: tt.end}, silences)
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("silenceShare(%d-%d) = %v, want %v", tt.start, tt.end, got, tt.want)
+		}
+	}
+}
+
+func TestFilterHallucinations(t *testing.T) {
+	a := NewApp()
+	doc := SubtitleDocument{Language: "ru", Cues: []SubtitleCue{
+		{Index: 1, Start: 0, End: 1000, Text: "Привет всем"},
+		{Index: 2, Start: 1000, End: 2000, Text: "привет всем!"},
+		{Index: 3, Start: 2000, End: 3000, Text: "Да да да"},
+		{Index: 4, Start: 3000, End: 9000, Text: "и вот так и вот так и вот так"},
+		{Index: 5, Start: 9000, End: 10000, Text: "Спасибо за просмотр!"},
+		{Index: 6, Start: 10000, End: 11000, Text: "Пока"},
+	}}
+	wantReasons := map[int]string{2: "repeated", 4: "loop", 5: "phrase"}
+
+	for _, mode := range []string{hallucinationRemove, hallucinationFlag} {
+		report, err := a.FilterHallucinations("", doc, HallucinationFilterOptions{Mode: mode})
+		if err != nil {
+			t.Fatalf("%s: %v", mode, err)
+		}
+		if len(report.Dropped) != len(wantReasons) {
+			t.Errorf("%s: dropped %d cues, want %d", mode, len(report.Dropped), len(wantReasons))
+		}
+		for _, d := range report.Dropped {
+			if wantReasons[d.Cue.Index] != d.Reason {
+				t.Errorf("%s: cue %d reason %q, want %q", mode, d.Cue.Index, d.Reason, wantReasons[d.Cue.Index])
+			}
+		}
+		cues := report.Document.Cues
+		switch mode {
+		case hallucinationRemove:
+			want := []string{"Привет всем", "Да да да", "Пока"}
+			if len(cues) != len(want) {
+				t.Fatalf("remove: %d cues left, want %d", len(cues), len(want))
+			}
+			for i, c := range cues {
+				if c.Text != want[i] || c.Index != i+1 {
+					t.Errorf("remove: cue %d = #%d %q, want #%d %q", i, c.Index, c.Text, i+1, want[i])
+				}
+			}
+		case hallucinationFlag:
+			if len(cues) != len(doc.Cues) {
+				t.Fatalf("flag: %d cues left, want %d", len(cues), len(doc.Cues))
+			}
+			for _, c := range cues {
+				if _, flagged := wantReasons[c.Index]; c.Review != flagged {
+					t.Errorf("flag: cue %d review = %v, want %v", c.Index, c.Review, flagged)
+				}
+			}
+		}
+	}
+
+	if _, err := a.FilterHallucinations("", doc, HallucinationFilterOptions{Mode: "drop"}); err == nil {
+		t.Errorf("unknown mode accepted")
+	}
+}
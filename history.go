@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HistoryEntry запись о запуске распознавания
+type HistoryEntry struct {
+	ID        string            `json:"id"`
+	Kind      string            `json:"kind"` // full, chunk, smart, transcribe
+	Started   time.Time         `json:"started"`
+	Input     string            `json:"input"`
+	InputHash string            `json:"inputHash,omitempty"`
+	Options   TranscribeOptions `json:"options"`
+	// Start и End — границы куска для chunk, с; ChunkSeconds и Workers — для smart
+	Start        int `json:"start,omitempty"`
+	End          int `json:"end,omitempty"`
+	ChunkSeconds int `json:"chunkSeconds,omitempty"`
+	Workers      int `json:"workers,omitempty"`
+
+	MediaDuration float64  `json:"mediaDuration"` // с
+	WallTime      float64  `json:"wallTime"`      // с
+	RTF           float64  `json:"rtf"`
+	Success       bool     `json:"success"`
+	Error         string   `json:"error,omitempty"`
+	Cues          int      `json:"cues"`
+	Outputs       []string `json:"outputs,omitempty"`
+}
+
+// HistoryFilter условия ListHistory; пустые поля не ограничивают выборку
+type HistoryFilter struct {
+	Model  string `json:"model,omitempty"`
+	Input  string `json:"input,omitempty"`  // подстрока пути
+	Status string `json:"status,omitempty"` // success или error
+	Limit  int    `json:"limit,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+}
+
+// ModelStats статистика запусков одной модели
+type ModelStats struct {
+	Model         string    `json:"model"`
+	Runs          int       `json:"runs"`
+	Failures      int       `json:"failures"`
+	MediaDuration float64   `json:"mediaDuration"` // суммарно по успешным, с
+	WallTime      float64   `json:"wallTime"`
+	RTF           float64   `json:"rtf"` // wallTime / mediaDuration
+	LastUsed      time.Time `json:"lastUsed"`
+}
+
+// getHistoryPath возвращает путь к журналу запусков (JSON Lines рядом с
+// настройками: запись дописывается одной строкой и не требует перезаписи файла)
+func (a *App) getHistoryPath() string {
+	return filepath.Join(filepath.Dir(a.getSettingsPath()), "history.jsonl")
+}
+
+// quickFileHash хэширует размер, начало и конец файла. Для видео в гигабайты
+// полный SHA-256 занял бы больше времени, чем сам запуск журнала. Файлы до
+// 2 МБ хэшируются целиком.
+func quickFileHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	const part = 1 << 20
+	h := sha256.New()
+	fmt.Fprintf(h, "%d:", info.Size())
+	if info.Size() <= 2*part {
+		if _, err := io.Copy(h, f); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+	if _, err := io.CopyN(h, f, part); err != nil {
+		return "", err
+	}
+	if _, err := f.Seek(-part, io.SeekEnd); err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// startHistory заводит запись о запуске; её дописывает finishHistory
+func (a *App) startHistory(kind, input string, opts TranscribeOptions) *HistoryEntry {
+	now := time.Now()
+	return &HistoryEntry{
+		ID:      fmt.Sprintf("run-%d", now.UnixNano()),
+		Kind:    kind,
+		Started: now,
+		Input:   input,
+		Options: opts,
+	}
+}
+
+// finishHistory завершает запись и сохраняет её в журнал. Ошибка записи
+// журнала только логируется: из-за неё не должен падать сам запуск.
+func (a *App) finishHistory(e *HistoryEntry, runErr error) {
+	e.WallTime = time.Since(e.Started).Seconds()
+	e.Success = runErr == nil
+	if runErr != nil {
+		e.Error = runErr.Error()
+	}
+	if e.Kind == "chunk" && e.End > e.Start {
+		e.MediaDuration = float64(e.End - e.Start)
+	} else if d, err := probeMediaDuration(e.Input); err == nil {
+		e.MediaDuration = d
+	}
+	if e.Success && e.MediaDuration > 0 {
+		e.RTF = e.WallTime / e.MediaDuration
+	}
+	if hash, err := quickFileHash(e.Input); err == nil {
+		e.InputHash = hash
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("[finishHistory] Ошибка сериализации: %v\n", err)
+		return
+	}
+	a.historyMu.Lock()
+	defer a.historyMu.Unlock()
+	f, err := os.OpenFile(a.getHistoryPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Printf("[finishHistory] Ошибка открытия журнала: %v\n", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		log.Printf("[finishHistory] Ошибка записи журнала: %v\n", err)
+	}
+}
+
+// loadHistory читает журнал; повреждённые строки (например, недописанные
+// при аварийном завершении) пропускаются
+func (a *App) loadHistory() ([]HistoryEntry, error) {
+	a.historyMu.Lock()
+	defer a.historyMu.Unlock()
+	f, err := os.Open(a.getHistoryPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var e HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			log.Printf("[loadHistory] Пропущена повреждённая запись: %v\n", err)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// ListHistory возвращает запуски, новые первыми
+func (a *App) ListHistory(filter HistoryFilter) ([]HistoryEntry, error) {
+	entries, err := a.loadHistory()
+	if err != nil {
+		log.Printf("[ListHistory] Ошибка чтения журнала: %v\n", err)
+		return nil, err
+	}
+	result := []HistoryEntry{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		switch {
+		case filter.Model != "" && e.Options.Model != filter.Model,
+			filter.Input != "" && !strings.Contains(e.Input, filter.Input),
+			filter.Status == "success" && !e.Success,
+			filter.Status == "error" && e.Success:
+			continue
+		}
+		result = append(result, e)
+	}
+	if filter.Offset > 0 {
+		result = result[min(filter.Offset, len(result)):]
+	}
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+// GetHistoryEntry возвращает запуск по идентификатору
+func (a *App) GetHistoryEntry(id string) (*HistoryEntry, error) {
+	entries, err := a.loadHistory()
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if entries[i].ID == id {
+			return &entries[i], nil
+		}
+	}
+	return nil, errors.New("history entry not found")
+}
+
+// GetModelStats возвращает статистику запусков по моделям, самые
+// используемые первыми
+func (a *App) GetModelStats() ([]ModelStats, error) {
+	entries, err := a.loadHistory()
+	if err != nil {
+		return nil, err
+	}
+	byModel := map[string]*ModelStats{}
+	for _, e := range entries {
+		s, ok := byModel[e.Options.Model]
+		if !ok {
+			s = &ModelStats{Model: e.Options.Model}
+			byModel[e.Options.Model] = s
+		}
+		s.Runs++
+		if e.Started.After(s.LastUsed) {
+			s.LastUsed = e.Started
+		}
+		if !e.Success {
+			s.Failures++
+			continue
+		}
+		s.MediaDuration += e.MediaDuration
+		s.WallTime += e.WallTime
+	}
+	stats := make([]ModelStats, 0, len(byModel))
+	for _, s := range byModel {
+		if s.MediaDuration > 0 {
+			s.RTF = s.WallTime / s.MediaDuration
+		}
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Runs != stats[j].Runs {
+			return stats[i].Runs > stats[j].Runs
+		}
+		return stats[i].Model < stats[j].Model
+	})
+	return stats, nil
+}
+
+// RerunHistoryEntry повторяет запуск с теми же файлом и настройками и
+// возвращает полученный документ. Повтор попадает в журнал отдельной записью.
+func (a *App) RerunHistoryEntry(id string) (*SubtitleDocument, error) {
+	log.Printf("[RerunHistoryEntry] Повтор запуска %s\n", id)
+	e, err := a.GetHistoryEntry(id)
+	if err != nil {
+		return nil, err
+	}
+	if hash, err := quickFileHash(e.Input); err != nil {
+		return nil, fmt.Errorf("input file is unavailable: %v", err)
+	} else if e.InputHash != "" && hash != e.InputHash {
+		log.Printf("[RerunHistoryEntry] ПРЕДУПРЕЖДЕНИЕ: файл %s изменился с прошлого запуска\n", e.Input)
+	}
+	var srt string
+	switch e.Kind {
+	case "transcribe":
+		return a.Transcribe(e.Input, e.Options)
+	case "full":
+		srt, err = a.GenerateSubtitles(context.Background(), e.Input, e.Options.Lang, e.Options.Model, e.Options.ForceRefresh)
+	case "chunk":
+		srt, err = a.GenerateSubtitlesChunk(context.Background(), e.Input, e.Options.Lang, e.Options.Model, e.Start, e.End, e.Options.ForceRefresh)
+	case "smart":
+		srt, err = a.GenerateSubtitlesSmart(e.Input, e.Options.Lang, e.Options.Model, e.ChunkSeconds, e.Workers, e.Options.ForceRefresh)
+	default:
+		return nil, fmt.Errorf("unknown run kind %q", e.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	cues, err := parseSRT(srt)
+	if err != nil {
+		return nil, err
+	}
+	return &SubtitleDocument{Language: e.Options.Lang, Model: e.Options.Model, Cues: cues}, nil
+}
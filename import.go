@@ -0,0 +1,677 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ImportIssue замечание разбора файла субтитров: error — фрагмент пропущен,
+// warning — фрагмент импортирован с исправлением
+type ImportIssue struct {
+	Line     int    `json:"line"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// ImportResult результат ImportSubtitles
+type ImportResult struct {
+	Document *SubtitleDocument `json:"document"`
+	Format   string            `json:"format"`   // srt, vtt, ass, sbv, ttml
+	Encoding string            `json:"encoding"` // utf-8, utf-16le, utf-16be, windows-1251
+	Issues   []ImportIssue     `json:"issues"`
+}
+
+// windows1251 символы кодовой страницы 1251 в диапазоне 0x80..0xBF;
+// 0xC0..0xFF — подряд А..я
+var windows1251 = [64]rune{
+	'Ђ', 'Ѓ', '‚', 'ѓ', '„', '…', '†', '‡', '€', '‰', 'Љ', '‹', 'Њ', 'Ќ', 'Ћ', 'Џ',
+	'ђ', '‘', '’', '“', '”', '•', '–', '—', utf8.RuneError, '™', 'љ', '›', 'њ', 'ќ', 'ћ', 'џ',
+	'\u00a0', 'Ў', 'ў', 'Ј', '¤', 'Ґ', '¦', '§', 'Ё', '©', 'Є', '«', '¬', '\u00ad', '®', 'Ї',
+	'°', '±', 'І', 'і', 'ґ', 'µ', '¶', '·', 'ё', '№', 'є', '»', 'ј', 'Ѕ', 'ѕ', 'ї',
+}
+
+// decodeSubtitleText определяет кодировку файла и переводит его в UTF-8.
+// Без BOM UTF-16 распознаётся по нулевым байтам, а всё, что не является
+// корректным UTF-8, считается Windows-1251.
+func decodeSubtitleText(data []byte) (string, string) {
+	switch {
+	case len(data) >= 3 && data[0] == 0xEF && data[1] == 0xBB && data[2] == 0xBF:
+		return string(data[3:]), "utf-8"
+	case len(data) >= 2 && data[0] == 0xFF && data[1] == 0xFE:
+		return decodeUTF16(data[2:], binary.LittleEndian), "utf-16le"
+	case len(data) >= 2 && data[0] == 0xFE && data[1] == 0xFF:
+		return decodeUTF16(data[2:], binary.BigEndian), "utf-16be"
+	}
+	// В UTF-16 текст из латиницы, цифр и разметки — это каждый второй нулевой байт
+	var zeroEven, zeroOdd int
+	for i := 0; i < len(data) && i < 4096; i++ {
+		if data[i] == 0 {
+			if i%2 == 0 {
+				zeroEven++
+			} else {
+				zeroOdd++
+			}
+		}
+	}
+	sample := min(len(data), 4096) / 2
+	switch {
+	case sample > 0 && zeroOdd > sample/4 && zeroEven == 0:
+		return decodeUTF16(data, binary.LittleEndian), "utf-16le"
+	case sample > 0 && zeroEven > sample/4 && zeroOdd == 0:
+		return decodeUTF16(data, binary.BigEndian), "utf-16be"
+	case utf8.Valid(data):
+		return string(data), "utf-8"
+	}
+	var b strings.Builder
+	b.Grow(len(data) * 2)
+	for _, c := range data {
+		switch {
+		case c < 0x80:
+			b.WriteByte(c)
+		case c < 0xC0:
+			b.WriteRune(windows1251[c-0x80])
+		default:
+			b.WriteRune(rune(c-0xC0) + 'А')
+		}
+	}
+	return b.String(), "windows-1251"
+}
+
+func decodeUTF16(data []byte, order binary.ByteOrder) string {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(units))
+}
+
+// detectSubtitleFormat определяет формат по расширению, а если оно
+// неизвестно — по содержимому
+func detectSubtitleFormat(path, text string) (string, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".srt", ".vtt", ".ass", ".sbv":
+		return ext[1:], nil
+	case ".ssa":
+		return "ass", nil
+	case ".ttml", ".dfxp", ".xml":
+		return "ttml", nil
+	}
+	head := strings.TrimSpace(text[:min(len(text), 512)])
+	switch {
+	case strings.HasPrefix(head, "WEBVTT"):
+		return "vtt", nil
+	case strings.HasPrefix(head, "[Script Info]"):
+		return "ass", nil
+	case strings.HasPrefix(head, "<"):
+		return "ttml", nil
+	case strings.Contains(head, "-->"):
+		return "srt", nil
+	case sbvTiming.MatchString(strings.SplitN(head, "\n", 2)[0]):
+		return "sbv", nil
+	}
+	return "", errors.New("unknown subtitle format")
+}
+
+// subtitleImporter накапливает реплики и замечания разбора
+type subtitleImporter struct {
+	cues     []SubtitleCue
+	issues   []ImportIssue
+	speakers map[string]string // имя спикера -> идентификатор
+	doc      SubtitleDocument
+}
+
+func (im *subtitleImporter) fail(line int, format string, args ...interface{}) {
+	im.issues = append(im.issues, ImportIssue{Line: line, Severity: "error", Message: fmt.Sprintf(format, args...)})
+}
+
+func (im *subtitleImporter) warn(line int, format string, args ...interface{}) {
+	im.issues = append(im.issues, ImportIssue{Line: line, Severity: "warning", Message: fmt.Sprintf(format, args...)})
+}
+
+// speaker возвращает идентификатор спикера по имени, заводя новый S<n>
+func (im *subtitleImporter) speaker(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ""
+	}
+	if id, ok := im.speakers[name]; ok {
+		return id
+	}
+	if im.speakers == nil {
+		im.speakers = map[string]string{}
+		im.doc.Speakers = map[string]string{}
+	}
+	id := fmt.Sprintf("S%d", len(im.speakers)+1)
+	im.speakers[name] = id
+	im.doc.Speakers[id] = name
+	return id
+}
+
+// add добавляет реплику, проверяя её время
+func (im *subtitleImporter) add(line int, c SubtitleCue) {
+	if strings.TrimSpace(c.Text) == "" {
+		im.warn(line, "empty cue skipped")
+		return
+	}
+	if c.End < c.Start {
+		im.warn(line, "end %s is before start %s, cue made zero-length", formatSRTTimestamp(c.End), formatSRTTimestamp(c.Start))
+		c.End = c.Start
+	}
+	c.Index = len(im.cues) + 1
+	im.cues = append(im.cues, c)
+}
+
+var (
+	sbvTiming   = regexp.MustCompile(`^\s*(\d+:\d{1,2}:\d{1,2}\.\d{1,3}),(\d+:\d{1,2}:\d{1,2}\.\d{1,3})\s*$`)
+	vttVoice    = regexp.MustCompile(`^<v(?:\.[^ >]*)?\s+([^>]*)>`)
+	vttTag      = regexp.MustCompile(`</?[a-z][^>]*>|<\d[\d:.]*>`)
+	assOverride = regexp.MustCompile(`\{[^}]*\}`)
+)
+
+// parseVTTTimestamp разбирает метку WebVTT, в которой часы необязательны
+func parseVTTTimestamp(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if strings.Count(s, ":") == 1 {
+		s = "0:" + s
+	}
+	return parseSRTTimestamp(s)
+}
+
+// parseBlocks разбирает форматы из блоков «время + текст», разделённых
+// пустыми строками: SRT, WebVTT и SBV. Строка времени начинает новую
+// реплику даже без пустой строки перед ней.
+func (im *subtitleImporter) parseBlocks(text, format string) {
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var cur *SubtitleCue
+	var lines []string
+	curLine, lineNo := 0, 0
+	skipBlock := false // блоки NOTE, STYLE и REGION в WebVTT
+	flush := func() {
+		if cur != nil {
+			cur.Text = strings.Join(lines, "\n")
+			if format == "vtt" {
+				if m := vttVoice.FindStringSubmatch(cur.Text); m != nil {
+					cur.Speaker = im.speaker(m[1])
+				}
+				cur.Text = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&nbsp;", " ", "&amp;", "&").Replace(vttTag.ReplaceAllString(cur.Text, ""))
+			}
+			im.add(curLine, *cur)
+		}
+		cur, lines = nil, nil
+	}
+
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if lineNo == 1 && format == "vtt" {
+			if !strings.HasPrefix(line, "WEBVTT") {
+				im.warn(lineNo, "missing WEBVTT header")
+			} else {
+				skipBlock = true // заголовок может продолжаться до пустой строки
+				continue
+			}
+		}
+		if strings.TrimSpace(line) == "" {
+			flush()
+			skipBlock = false
+			continue
+		}
+		if skipBlock {
+			continue
+		}
+
+		var start, end int64
+		var timing, bad bool
+		switch {
+		case format == "sbv":
+			if m := sbvTiming.FindStringSubmatch(line); m != nil {
+				timing = true
+				var err1, err2 error
+				start, err1 = parseSRTTimestamp(m[1])
+				end, err2 = parseSRTTimestamp(m[2])
+				bad = err1 != nil || err2 != nil
+			}
+		case strings.Contains(line, "-->"):
+			timing = true
+			parts := strings.SplitN(line, "-->", 2)
+			// После конечной метки могут идти координаты или настройки реплики
+			endField := strings.Fields(parts[1])
+			var err1, err2 error
+			start, err1 = parseVTTTimestamp(parts[0])
+			if len(endField) == 0 {
+				err2 = errors.New("missing end timestamp")
+			} else {
+				end, err2 = parseVTTTimestamp(endField[0])
+			}
+			if err1 != nil || err2 != nil {
+				bad = true
+			}
+		}
+
+		switch {
+		case timing && bad:
+			flush()
+			im.fail(lineNo, "invalid timing line %q, cue skipped", line)
+			skipBlock = true
+		case timing:
+			if cur != nil {
+				im.warn(lineNo, "missing blank line before cue")
+				// Номер следующей реплики SRT попал в текст предыдущей
+				if n := len(lines); n > 0 && format == "srt" {
+					if _, err := strconv.Atoi(lines[n-1]); err == nil {
+						lines = lines[:n-1]
+					}
+				}
+			}
+			flush()
+			cur, curLine = &SubtitleCue{Start: start, End: end}, lineNo
+		case cur != nil:
+			lines = append(lines, strings.TrimSpace(line))
+		case format == "vtt" && (strings.HasPrefix(line, "NOTE") || line == "STYLE" || line == "REGION"):
+			skipBlock = true
+		default:
+			// Номер реплики в SRT или идентификатор в WebVTT
+			if _, err := strconv.Atoi(strings.TrimSpace(line)); err != nil && format == "srt" {
+				im.fail(lineNo, "unexpected text %q outside a cue", line)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		im.fail(lineNo, "read error: %v", err)
+	}
+	flush()
+}
+
+// parseASSTimestamp разбирает метку ASS вида H:MM:SS.cc
+func parseASSTimestamp(s string) (int64, error) {
+	ms, err := parseSRTTimestamp(s)
+	if err != nil || !strings.Contains(s, ".") {
+		return 0, fmt.Errorf("invalid ASS timestamp %q", s)
+	}
+	return ms, nil
+}
+
+// assPlainText переводит текст события ASS в обычный: теги оформления
+// отбрасываются, \N становится переводом строки
+func assPlainText(s string) string {
+	s = assOverride.ReplaceAllString(s, "")
+	s = strings.NewReplacer(`\N`, "\n", `\n`, "\n", `\h`, " ").Replace(s)
+	return strings.TrimSpace(s)
+}
+
+// splitASSFormat разбирает строку Format в имена полей в нижнем регистре
+func splitASSFormat(value string) []string {
+	fields := strings.Split(value, ",")
+	for i := range fields {
+		fields[i] = strings.ToLower(strings.TrimSpace(fields[i]))
+	}
+	return fields
+}
+
+// ssaAlignment переводит выравнивание SSA (1-3 снизу, 5-7 сверху, 9-11
+// посередине) в раскладку цифровой клавиатуры V4+
+var ssaAlignment = map[string]string{
+	"1": "1", "2": "2", "3": "3",
+	"5": "7", "6": "8", "7": "9",
+	"9": "4", "10": "5", "11": "6",
+}
+
+// ssaStyleToASS переводит строку стиля SSA с полями format в строку
+// [V4+ Styles] с полями assStyleFormat
+func ssaStyleToASS(format []string, value string) (string, error) {
+	fields := strings.SplitN(value, ",", len(format))
+	if len(fields) < len(format) {
+		return "", fmt.Errorf("Style has %d fields, expected %d", len(fields), len(format))
+	}
+	// Значения по умолчанию для полей, которых нет в SSA или в файле
+	v := map[string]string{
+		"name": "Default", "fontname": "Arial", "fontsize": "20",
+		"primarycolour": "&H00FFFFFF", "secondarycolour": "&H000000FF",
+		"outlinecolour": "&H00000000", "backcolour": "&H00000000",
+		"bold": "0", "italic": "0", "underline": "0", "strikeout": "0",
+		"scalex": "100", "scaley": "100", "spacing": "0", "angle": "0",
+		"borderstyle": "1", "outline": "2", "shadow": "0", "alignment": "2",
+		"marginl": "10", "marginr": "10", "marginv": "10", "encoding": "1",
+	}
+	for i, name := range format {
+		v[name] = strings.TrimSpace(fields[i])
+	}
+	// В SSA контур рисуется цветом TertiaryColour
+	if c, ok := v["tertiarycolour"]; ok {
+		v["outlinecolour"] = c
+	}
+	if a, ok := ssaAlignment[v["alignment"]]; ok {
+		v["alignment"] = a
+	}
+	names := splitASSFormat(strings.TrimPrefix(assStyleFormat, "Format:"))
+	out := make([]string, len(names))
+	for i, name := range names {
+		out[i] = v[name]
+	}
+	return "Style: " + strings.Join(out, ","), nil
+}
+
+// parseASS разбирает ASS/SSA. Стили сохраняются в документе, теги
+// оформления внутри реплик — нет.
+func (im *subtitleImporter) parseASS(text string) {
+	sheet := &StyleSheet{Format: assStyleFormat}
+	section := ""
+	var eventFormat, ssaFormat []string
+	lineNo := 0
+	for _, line := range strings.Split(text, "\n") {
+		lineNo++
+		line = strings.TrimSpace(strings.TrimRight(line, "\r"))
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(line)
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			im.warn(lineNo, "unexpected line %q", line)
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch section {
+		case "[script info]":
+			switch key {
+			case "PlayResX":
+				sheet.PlayResX, _ = strconv.Atoi(value)
+			case "PlayResY":
+				sheet.PlayResY, _ = strconv.Atoi(value)
+			}
+		case "[v4+ styles]":
+			switch key {
+			case "Format":
+				sheet.Format = "Format: " + value
+			case "Style":
+				sheet.Styles = append(sheet.Styles, "Style: "+value)
+			}
+		case "[v4 styles]":
+			// Стили SSA переводятся в формат V4+, в котором пишет экспорт
+			switch key {
+			case "Format":
+				ssaFormat = splitASSFormat(value)
+			case "Style":
+				if ssaFormat == nil {
+					im.fail(lineNo, "Style before Format line")
+					continue
+				}
+				style, err := ssaStyleToASS(ssaFormat, value)
+				if err != nil {
+					im.fail(lineNo, "%v, style skipped", err)
+					continue
+				}
+				sheet.Styles = append(sheet.Styles, style)
+			}
+		case "[events]":
+			switch key {
+			case "Format":
+				eventFormat = splitASSFormat(value)
+			case "Dialogue":
+				if eventFormat == nil {
+					im.fail(lineNo, "Dialogue before Format line")
+					continue
+				}
+				fields := strings.SplitN(value, ",", len(eventFormat))
+				if len(fields) < len(eventFormat) {
+					im.fail(lineNo, "Dialogue has %d fields, expected %d", len(fields), len(eventFormat))
+					continue
+				}
+				ev := map[string]string{}
+				for i, name := range eventFormat {
+					ev[name] = strings.TrimSpace(fields[i])
+				}
+				start, err1 := parseASSTimestamp(ev["start"])
+				end, err2 := parseASSTimestamp(ev["end"])
+				if err1 != nil || err2 != nil {
+					im.fail(lineNo, "invalid timing %q - %q, event skipped", ev["start"], ev["end"])
+					continue
+				}
+				im.add(lineNo, SubtitleCue{
+					Start:   start,
+					End:     end,
+					Text:    assPlainText(ev["text"]),
+					Style:   strings.TrimPrefix(ev["style"], "*"),
+					Speaker: im.speaker(ev["name"]),
+				})
+			}
+		}
+	}
+	if len(sheet.Styles) > 0 {
+		im.doc.Styles = sheet
+		names := sheet.names()
+		for _, c := range im.cues {
+			if c.Style != "" && !names[c.Style] {
+				im.warn(0, "style %q is not defined, cues using it will get the first style", c.Style)
+				names[c.Style] = true // одно замечание на стиль
+			}
+		}
+	} else {
+		im.warn(0, "no styles section, default styles will be used")
+	}
+	// В ASS события не обязаны идти по порядку
+	sortCuesByStart(im.cues)
+}
+
+// sortCuesByStart упорядочивает реплики по времени начала
+func sortCuesByStart(cues []SubtitleCue) {
+	sort.SliceStable(cues, func(i, j int) bool { return cues[i].Start < cues[j].Start })
+	renumberCues(cues)
+}
+
+// ttmlTimeExpr разбирает выражение времени TTML: часы:минуты:секунды(.доли
+// или :кадры) либо смещение с единицей h, m, s, ms, f, t
+func ttmlTimeExpr(s string, frameRate, tickRate float64) (int64, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, ":") {
+		parts := strings.Split(s, ":")
+		if len(parts) == 4 {
+			frames, err := strconv.ParseFloat(parts[3], 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid TTML time %q", s)
+			}
+			base, err := ttmlTimeExpr(strings.Join(parts[:3], ":"), frameRate, tickRate)
+			return base + int64(frames/frameRate*1000), err
+		}
+		if len(parts) != 3 {
+			return 0, fmt.Errorf("invalid TTML time %q", s)
+		}
+		h, err1 := strconv.Atoi(parts[0])
+		m, err2 := strconv.Atoi(parts[1])
+		sec, err3 := strconv.ParseFloat(parts[2], 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			return 0, fmt.Errorf("invalid TTML time %q", s)
+		}
+		return int64(h*3600000+m*60000) + int64(sec*1000+0.5), nil
+	}
+	units := map[string]float64{"h": 3600000, "m": 60000, "s": 1000, "ms": 1, "f": 1000 / frameRate, "t": 1000 / tickRate}
+	for _, unit := range []string{"ms", "h", "m", "s", "f", "t"} {
+		if num, ok := strings.CutSuffix(s, unit); ok {
+			v, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid TTML time %q", s)
+			}
+			return int64(v*units[unit] + 0.5), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid TTML time %q", s)
+}
+
+// ttmlScope интервал элемента TTML; end < 0 — не ограничен
+type ttmlScope struct {
+	begin, end int64
+}
+
+// ttmlTiming вычисляет интервал элемента по атрибутам begin, end и dur.
+// Время отсчитывается от начала родителя; элемент без begin начинается
+// вместе с родителем, без end и dur — заканчивается вместе с ним.
+func ttmlTiming(attr map[string]string, parent ttmlScope, frameRate, tickRate float64) (ttmlScope, error) {
+	scope := ttmlScope{begin: parent.begin, end: parent.end}
+	if v, ok := attr["begin"]; ok {
+		offset, err := ttmlTimeExpr(v, frameRate, tickRate)
+		if err != nil {
+			return scope, err
+		}
+		scope.begin = parent.begin + offset
+	}
+	if v, ok := attr["end"]; ok {
+		end, err := ttmlTimeExpr(v, frameRate, tickRate)
+		if err != nil {
+			return scope, err
+		}
+		scope.end = parent.begin + end
+	} else if v, ok := attr["dur"]; ok {
+		dur, err := ttmlTimeExpr(v, frameRate, tickRate)
+		if err != nil {
+			return scope, err
+		}
+		scope.end = scope.begin + dur
+	}
+	if parent.end >= 0 && (scope.end < 0 || scope.end > parent.end) {
+		scope.end = parent.end
+	}
+	return scope, nil
+}
+
+// parseTTML разбирает TTML/DFXP: реплики — элементы <p>. Время может быть
+// задано на самом <p> или унаследовано от <body> и <div>.
+func (im *subtitleImporter) parseTTML(text string) {
+	dec := xml.NewDecoder(strings.NewReader(text))
+	dec.Strict = false
+	frameRate, tickRate := 30.0, 1.0
+	scopes := []ttmlScope{{begin: 0, end: -1}}
+	var cur *SubtitleCue
+	var b strings.Builder
+	curLine := 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		line, _ := dec.InputPos()
+		if err != nil {
+			im.fail(line, "XML error: %v", err)
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			attr := map[string]string{}
+			for _, a := range t.Attr {
+				attr[a.Name.Local] = a.Value
+			}
+			switch t.Name.Local {
+			case "tt":
+				if v, err := strconv.ParseFloat(attr["frameRate"], 64); err == nil && v > 0 {
+					frameRate = v
+					tickRate = v
+				}
+				if v, err := strconv.ParseFloat(attr["tickRate"], 64); err == nil && v > 0 {
+					tickRate = v
+				}
+			case "body", "div":
+				scope, err := ttmlTiming(attr, scopes[len(scopes)-1], frameRate, tickRate)
+				if err != nil {
+					im.warn(line, "invalid timing on <%s>: %v", t.Name.Local, err)
+				}
+				scopes = append(scopes, scope)
+			case "p":
+				scope, err := ttmlTiming(attr, scopes[len(scopes)-1], frameRate, tickRate)
+				scopes = append(scopes, scope)
+				if err == nil && scope.end < 0 {
+					err = errors.New("no end or dur")
+				}
+				if err != nil {
+					im.fail(line, "paragraph without valid timing (begin=%q), skipped", attr["begin"])
+					continue
+				}
+				c := SubtitleCue{Start: scope.begin, End: scope.end, Speaker: im.speaker(attr["agent"])}
+				cur, curLine = &c, line
+				b.Reset()
+			case "br":
+				if cur != nil {
+					b.WriteString("\n")
+				}
+			}
+		case xml.CharData:
+			if cur != nil {
+				// Пробелы схлопываются при сборке строк реплики
+				b.WriteString(strings.NewReplacer("\n", " ", "\r", " ", "\t", " ").Replace(string(t)))
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "body", "div", "p":
+				if len(scopes) > 1 {
+					scopes = scopes[:len(scopes)-1]
+				}
+			}
+			if t.Name.Local == "p" && cur != nil {
+				lines := strings.Split(b.String(), "\n")
+				for i := range lines {
+					lines[i] = strings.Join(strings.Fields(lines[i]), " ")
+				}
+				cur.Text = strings.Join(lines, "\n")
+				im.add(curLine, *cur)
+				cur = nil
+			}
+		}
+	}
+	sortCuesByStart(im.cues)
+}
+
+// ImportSubtitles читает файл субтитров SRT, WebVTT, ASS/SSA, SBV или
+// TTML в документ. Ошибочные фрагменты пропускаются и попадают в отчёт
+// Issues; ошибка возвращается, только если не удалось прочитать ни одной
+// реплики.
+func (a *App) ImportSubtitles(path string) (*ImportResult, error) {
+	log.Printf("[ImportSubtitles] Импорт: %s\n", path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("[ImportSubtitles] Ошибка чтения: %v\n", err)
+		return nil, err
+	}
+	text, encoding := decodeSubtitleText(data)
+	text = strings.TrimPrefix(text, "\ufeff")
+	format, err := detectSubtitleFormat(path, text)
+	if err != nil {
+		return nil, err
+	}
+
+	im := &subtitleImporter{}
+	switch format {
+	case "ass":
+		im.parseASS(text)
+	case "ttml":
+		im.parseTTML(text)
+	default:
+		im.parseBlocks(text, format)
+	}
+	im.doc.Cues = im.cues
+	result := &ImportResult{Document: &im.doc, Format: format, Encoding: encoding, Issues: im.issues}
+	log.Printf("[ImportSubtitles] Формат %s, кодировка %s, реплик %d, замечаний %d\n", format, encoding, len(im.cues), len(im.issues))
+	if len(im.cues) == 0 {
+		if len(im.issues) > 0 {
+			first := im.issues[0]
+			return result, fmt.Errorf("no cues imported; line %d: %s", first.Line, first.Message)
+		}
+		return result, errors.New("no cues imported")
+	}
+	return result, nil
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestParseVTTTimestamp(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"00:01.000", 1000, false},
+		{"01:02.5", 62500, false},
+		{"01:00:00.000", 3600000, false},
+		{"1:02", 62000, false},
+		{"abc", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseVTTTimestamp(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseVTTTimestamp(%q) = %d, %v; want %d, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseASSTimestamp(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"0:00:01.00", 1000, false},
+		{"0:00:01.50", 1500, false},
+		{"0:00:01.5", 1500, false},
+		{"1:02:03.04", 3723040, false},
+		{"0:00:01", 0, true},
+		{"x", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseASSTimestamp(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseASSTimestamp(%q) = %d, %v; want %d, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestTTMLTimeExpr(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"00:00:01.500", 1500, false},
+		{"00:00:01:15", 1500, false}, // 15 кадров при 30 кадр/с
+		{"1.5s", 1500, false},
+		{"250ms", 250, false},
+		{"2m", 120000, false},
+		{"30f", 1000, false},
+		{"10t", 10000, false},
+		{"abc", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ttmlTimeExpr(tt.in, 30, 1)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ttmlTimeExpr(%q) = %d, %v; want %d, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+type wantCue struct {
+	start, end int64
+	text       string
+}
+
+func checkCues(t *testing.T, got []SubtitleCue, want []wantCue) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d cues, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		c := got[i]
+		if c.Start != w.start || c.End != w.end || c.Text != w.text {
+			t.Errorf("cue %d = {%d %d %q}, want {%d %d %q}", i+1, c.Start, c.End, c.Text, w.start, w.end, w.text)
+		}
+	}
+}
+
+func TestParseSBV(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []wantCue
+	}{
+		{
+			name: "basic",
+			in:   "0:00:01.000,0:00:02.500\nHello\n\n0:00:03.000,0:00:04.000\nTwo\nlines\n",
+			want: []wantCue{{1000, 2500, "Hello"}, {3000, 4000, "Two\nlines"}},
+		},
+		{
+			name: "short fractions",
+			in:   "0:00:01.5,0:00:02.25\nShort\n",
+			want: []wantCue{{1500, 2250, "Short"}},
+		},
+		{
+			name: "no blank line between cues",
+			in:   "0:00:01.000,0:00:02.000\nA\n0:00:02.000,0:00:03.000\nB\n",
+			want: []wantCue{{1000, 2000, "A"}, {2000, 3000, "B"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			im := &subtitleImporter{}
+			im.parseBlocks(tt.in, "sbv")
+			checkCues(t, im.cues, tt.want)
+		})
+	}
+}
+
+func TestParseTTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []wantCue
+	}{
+		{
+			name: "begin and end",
+			in: `<tt xmlns="http://www.w3.org/ns/ttml"><body><div>
+<p begin="00:00:01.000" end="00:00:02.000">One<br/>two</p>
+<p begin="3s" dur="1.5s">Three</p>
+</div></body></tt>`,
+			want: []wantCue{{1000, 2000, "One\ntwo"}, {3000, 4500, "Three"}},
+		},
+		{
+			name: "timing relative to div",
+			in: `<tt xmlns="http://www.w3.org/ns/ttml"><body><div begin="10s">
+<p begin="1s" end="2s">Shifted</p>
+</div></body></tt>`,
+			want: []wantCue{{11000, 12000, "Shifted"}},
+		},
+		{
+			name: "end inherited from div",
+			in: `<tt xmlns="http://www.w3.org/ns/ttml"><body><div begin="5s" end="8s">
+<p>Whole div</p>
+</div></body></tt>`,
+			want: []wantCue{{5000, 8000, "Whole div"}},
+		},
+		{
+			name: "end clipped by body",
+			in: `<tt xmlns="http://www.w3.org/ns/ttml"><body dur="3s"><div>
+<p begin="1s" end="5s">Clipped</p>
+</div></body></tt>`,
+			want: []wantCue{{1000, 3000, "Clipped"}},
+		},
+		{
+			name: "frame rate",
+			in: `<tt xmlns="http://www.w3.org/ns/ttml" xmlns:ttp="http://www.w3.org/ns/ttml#parameter" ttp:frameRate="25"><body>
+<p begin="00:00:01:00" end="00:00:01:25">Frames</p>
+</body></tt>`,
+			want: []wantCue{{1000, 2000, "Frames"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			im := &subtitleImporter{}
+			im.parseTTML(tt.in)
+			checkCues(t, im.cues, tt.want)
+		})
+	}
+}
+
+func TestParseTTMLWithoutTiming(t *testing.T) {
+	im := &subtitleImporter{}
+	im.parseTTML(`<tt><body><div><p>No time</p></div></body></tt>`)
+	if len(im.cues) != 0 || len(im.issues) != 1 || im.issues[0].Severity != "error" {
+		t.Errorf("cues %+v, issues %+v; want no cues and one error", im.cues, im.issues)
+	}
+}
+
+func TestParseSSAStyles(t *testing.T) {
+	in := `[Script Info]
+ScriptType: v4.00
+PlayResX: 640
+PlayResY: 480
+
+[V4 Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, TertiaryColour, BackColour, Bold, Italic, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, AlphaLevel, Encoding
+Style: Top,Arial,28,16777215,65535,255,0,-1,0,1,2,1,6,10,10,15,0,204
+
+[Events]
+Format: Marked, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+Dialogue: Marked=0,0:00:01.00,0:00:02.50,Top,,0,0,0,,{\i1}Hello{\i0}, world
+`
+	im := &subtitleImporter{}
+	im.parseASS(in)
+	checkCues(t, im.cues, []wantCue{{1000, 2500, "Hello, world"}})
+	sheet := im.doc.Styles
+	if sheet == nil || len(sheet.Styles) != 1 {
+		t.Fatalf("styles = %+v, want one style", sheet)
+	}
+	if sheet.Format != assStyleFormat {
+		t.Errorf("format = %q, want V4+ format", sheet.Format)
+	}
+	want := "Style: Top,Arial,28,16777215,65535,255,0,-1,0,0,0,100,100,0,0,1,2,1,8,10,10,15,204"
+	if sheet.Styles[0] != want {
+		t.Errorf("style =\n%s\nwant\n%s", sheet.Styles[0], want)
+	}
+	got := len(strings.Split(sheet.Styles[0], ","))
+	if n := len(strings.Split(assStyleFormat, ",")); got != n {
+		t.Errorf("style has %d fields, format has %d", got, n)
+	}
+}
+
+func encodeUTF16(s string, bigEndian bool) []byte {
+	var out []byte
+	for _, u := range utf16.Encode([]rune(s)) {
+		if bigEndian {
+			out = append(out, byte(u>>8), byte(u))
+		} else {
+			out = append(out, byte(u), byte(u>>8))
+		}
+	}
+	return out
+}
+
+func TestDecodeSubtitleText(t *testing.T) {
+	// «Ёлка «ёж» №1, Привет» в кодировке Windows-1251
+	cp1251 := []byte{0xA8, 0xEB, 0xEA, 0xE0, ' ', 0xAB, 0xB8, 0xE6, 0xBB, ' ', 0xB9, '1', ',', ' ', 0xCF, 0xF0, 0xE8, 0xE2, 0xE5, 0xF2}
+	srt := "1\n00:00:01,000 --> 00:00:02,000\nПривет\n"
+	tests := []struct {
+		name     string
+		data     []byte
+		text     string
+		encoding string
+	}{
+		{"windows-1251", cp1251, "Ёлка «ёж» №1, Привет", "windows-1251"},
+		{"utf-8", []byte("Ёлка «ёж»"), "Ёлка «ёж»", "utf-8"},
+		{"utf-8 bom", append([]byte{0xEF, 0xBB, 0xBF}, "Привет"...), "Привет", "utf-8"},
+		{"utf-16le bom", append([]byte{0xFF, 0xFE}, encodeUTF16(srt, false)...), srt, "utf-16le"},
+		{"utf-16be bom", append([]byte{0xFE, 0xFF}, encodeUTF16(srt, true)...), srt, "utf-16be"},
+		{"utf-16le without bom", encodeUTF16(srt, false), srt, "utf-16le"},
+		{"utf-16be without bom", encodeUTF16(srt, true), srt, "utf-16be"},
+		{"ascii", []byte("hello"), "hello", "utf-8"},
+	}
+	for _, tt := range tests {
+		text, encoding := decodeSubtitleText(tt.data)
+		if text != tt.text || encoding != tt.encoding {
+			t.Errorf("%s: decodeSubtitleText = %q, %s; want %q, %s", tt.name, text, encoding, tt.text, tt.encoding)
+		}
+	}
+}
+
+func TestImportSubtitlesWindows1251(t *testing.T) {
+	// «Привет» и «Ёж» в Windows-1251
+	data := []byte("1\r\n00:00:01,000 --> 00:00:02,000\r\n\xCF\xF0\xE8\xE2\xE5\xF2\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\n\xA8\xE6\r\n")
+	path := filepath.Join(t.TempDir(), "legacy.srt")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := NewApp().ImportSubtitles(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Encoding != "windows-1251" || result.Format != "srt" {
+		t.Errorf("encoding %s, format %s", result.Encoding, result.Format)
+	}
+	cues := result.Document.Cues
+	if len(cues) != 2 || cues[0].Text != "Привет" || cues[1].Text != "Ёж" {
+		t.Errorf("cues = %+v", cues)
+	}
+}
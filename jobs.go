@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+)
+
+// startJob регистрирует отменяемую задачу с ключом key (например, путём
+// выходного файла) и возвращает её контекст и функцию завершения
+func (a *App) startJob(key string) (context.Context, func(), error) {
+	a.jobsMu.Lock()
+	defer a.jobsMu.Unlock()
+	if a.jobs == nil {
+		a.jobs = map[string]context.CancelFunc{}
+	}
+	if _, busy := a.jobs[key]; busy {
+		return nil, nil, errors.New("Задача уже выполняется: " + key)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.jobs[key] = cancel
+	done := func() {
+		a.jobsMu.Lock()
+		defer a.jobsMu.Unlock()
+		delete(a.jobs, key)
+		cancel()
+	}
+	return ctx, done, nil
+}
+
+// CancelJob отменяет выполняющуюся задачу; возвращает false, если её нет
+func (a *App) CancelJob(key string) bool {
+	a.jobsMu.Lock()
+	defer a.jobsMu.Unlock()
+	cancel, ok := a.jobs[key]
+	if ok {
+		log.Printf("[CancelJob] Отмена задачи %s\n", key)
+		cancel()
+	}
+	return ok
+}
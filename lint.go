@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Уровни серьёзности замечаний линтера
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// Действия автоисправления
+const (
+	fixDelete  = "delete"
+	fixSetEnd  = "setEnd"
+	fixSetText = "setText"
+)
+
+// LintFix автоисправление для реплики из LintIssue
+type LintFix struct {
+	Action string `json:"action"` // delete, setEnd, setText
+	End    int64  `json:"end,omitempty"`
+	Text   string `json:"text,omitempty"`
+}
+
+// LintIssue замечание к реплике с номером CueIndex
+type LintIssue struct {
+	CueIndex int      `json:"cueIndex"`
+	Rule     string   `json:"rule"`
+	Severity string   `json:"severity"`
+	Message  string   `json:"message"`
+	Fix      *LintFix `json:"fix,omitempty"`
+}
+
+// LintSubtitles проверяет документ на соответствие профилю и типичные ошибки whisper.
+// Незаданные поля профиля дополняются (см. withDefaults), некорректный профиль —
+// ошибка. Замечания отсортированы по номеру реплики.
+func (a *App) LintSubtitles(doc SubtitleDocument, profile SubtitleProfile) ([]LintIssue, error) {
+	profile = profile.withDefaults()
+	if err := profile.validate(); err != nil {
+		log.Printf("[LintSubtitles] Некорректный профиль: %v\n", err)
+		return nil, err
+	}
+	var issues []LintIssue
+	phrases := a.hallucinationPhrases()
+	add := func(c SubtitleCue, rule, severity string, fix *LintFix, format string, args ...interface{}) {
+		issues = append(issues, LintIssue{CueIndex: c.Index, Rule: rule, Severity: severity, Message: fmt.Sprintf(format, args...), Fix: fix})
+	}
+
+	for i, c := range doc.Cues {
+		var next *SubtitleCue
+		if i+1 < len(doc.Cues) {
+			next = &doc.Cues[i+1]
+		}
+		// Граница, до которой можно продлить реплику; MaxDuration 0 — без предела
+		limit := int64(math.MaxInt64)
+		if profile.MaxDuration > 0 {
+			limit = c.Start + max(profile.MaxDuration, profile.MinDuration)
+		}
+		if next != nil && next.Start > c.Start {
+			limit = min(limit, next.Start-profile.MinGap)
+		}
+		dur := c.End - c.Start
+		text := strings.TrimSpace(c.Text)
+
+		if text == "" {
+			add(c, "empty", severityError, &LintFix{Action: fixDelete}, "Пустая реплика")
+			continue
+		}
+		if dur <= 0 {
+			// Реплика получает минимальную длительность или время на чтение,
+			// но не залезает на следующую
+			want := c.Start + max(profile.MinDuration, readingDuration(text, profile.MaxCPS))
+			add(c, "non-positive-duration", severityError, &LintFix{Action: fixSetEnd, End: max(min(want, limit), c.Start+1)},
+				"Длительность %d мс", dur)
+		} else if dur < profile.MinDuration {
+			var fix *LintFix
+			if limit > c.End {
+				fix = &LintFix{Action: fixSetEnd, End: min(c.Start+profile.MinDuration, limit)}
+			}
+			add(c, "min-duration", severityWarning, fix, "Длительность %d мс меньше %d мс", dur, profile.MinDuration)
+		} else if profile.MaxDuration > 0 && dur > profile.MaxDuration {
+			add(c, "max-duration", severityWarning, &LintFix{Action: fixSetEnd, End: c.Start + profile.MaxDuration},
+				"Длительность %d мс больше %d мс", dur, profile.MaxDuration)
+		}
+
+		if profile.MaxCPS > 0 && dur > 0 {
+			chars := utf8.RuneCountInString(strings.ReplaceAll(text, "\n", ""))
+			if cps := float64(chars) * 1000 / float64(dur); cps > profile.MaxCPS {
+				var fix *LintFix
+				if need := c.Start + readingDuration(text, profile.MaxCPS); need <= limit {
+					fix = &LintFix{Action: fixSetEnd, End: need}
+				}
+				add(c, "cps", severityWarning, fix, "Скорость чтения %.1f симв/с больше %.1f", cps, profile.MaxCPS)
+			}
+		}
+
+		lines := strings.Split(text, "\n")
+		longest := 0
+		for _, l := range lines {
+			longest = max(longest, utf8.RuneCountInString(l))
+		}
+		if longest > profile.MaxCharsPerLine || len(lines) > profile.MaxLines {
+			var fix *LintFix
+			if wrapped := wrapText(flattenText(text), profile.MaxCharsPerLine, profile.MaxLines); wrapped != text && fitsProfile(wrapped, &profile) {
+				fix = &LintFix{Action: fixSetText, Text: wrapped}
+			}
+			if longest > profile.MaxCharsPerLine {
+				add(c, "line-length", severityWarning, fix, "Строка %d символов длиннее %d", longest, profile.MaxCharsPerLine)
+			} else {
+				add(c, "line-count", severityWarning, fix, "%d строк больше %d", len(lines), profile.MaxLines)
+			}
+		}
+
+		if isKnownHallucination(text, doc.Language, phrases) {
+			add(c, "hallucination", severityWarning, &LintFix{Action: fixDelete}, "Типичная галлюцинация whisper: %q", text)
+		} else if i > 0 && normalizeCueText(text) == normalizeCueText(doc.Cues[i-1].Text) {
+			add(c, "repeated", severityWarning, &LintFix{Action: fixDelete}, "Повтор предыдущей реплики")
+		}
+
+		if next == nil {
+			continue
+		}
+		switch gap := next.Start - c.End; {
+		case next.Start < c.Start:
+			add(*next, "out-of-order", severityError, nil, "Реплика начинается раньше предыдущей")
+		case gap < 0:
+			add(c, "overlap", severityError, &LintFix{Action: fixSetEnd, End: max(next.Start-profile.MinGap, c.Start+1)},
+				"Пересекается со следующей репликой на %d мс", -gap)
+		case gap < profile.MinGap:
+			var fix *LintFix
+			if next.Start-profile.MinGap > c.Start {
+				fix = &LintFix{Action: fixSetEnd, End: next.Start - profile.MinGap}
+			}
+			add(c, "min-gap", severityWarning, fix, "Пауза %d мс меньше %d мс", gap, profile.MinGap)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].CueIndex < issues[j].CueIndex })
+	log.Printf("[LintSubtitles] Профиль %s: %d замечаний для %d реплик\n", profile.Name, len(issues), len(doc.Cues))
+	return issues, nil
+}
+
+// ApplyLintFixes применяет автоисправления из замечаний и возвращает новый документ.
+// Исправления времени одной реплики объединяются: из продлений берётся самое
+// позднее окончание (так выполняются и минимальная длительность, и скорость
+// чтения), а укорачивания (пересечение, пауза, максимальная длительность)
+// задают предел, за который окончание не выходит.
+func (a *App) ApplyLintFixes(doc SubtitleDocument, issues []LintIssue) *SubtitleDocument {
+	byIndex := map[int]int{}
+	cues := make([]SubtitleCue, len(doc.Cues))
+	copy(cues, doc.Cues)
+	for i, c := range cues {
+		byIndex[c.Index] = i
+	}
+
+	deleted := map[int]bool{}
+	extend := map[int]int64{}
+	limit := map[int]int64{}
+	applied := 0
+	for _, issue := range issues {
+		i, ok := byIndex[issue.CueIndex]
+		if !ok || issue.Fix == nil {
+			continue
+		}
+		switch issue.Fix.Action {
+		case fixDelete:
+			deleted[i] = true
+		case fixSetEnd:
+			end := issue.Fix.End
+			if end > doc.Cues[i].End {
+				if cur, ok := extend[i]; !ok || end > cur {
+					extend[i] = end
+				}
+			} else if cur, ok := limit[i]; !ok || end < cur {
+				limit[i] = end
+			}
+		case fixSetText:
+			cues[i].Text = issue.Fix.Text
+		default:
+			continue
+		}
+		applied++
+	}
+	for i := range cues {
+		if end, ok := extend[i]; ok {
+			cues[i].End = end
+		}
+		if end, ok := limit[i]; ok && end < cues[i].End {
+			cues[i].End = end
+		}
+	}
+
+	result := cues[:0]
+	for i, c := range cues {
+		if !deleted[i] {
+			result = append(result, c)
+		}
+	}
+	renumberCues(result)
+	doc.Cues = result
+	log.Printf("[ApplyLintFixes] Применено исправлений: %d\n", applied)
+	return &doc
+}
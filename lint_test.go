@@ -0,0 +1,100 @@
+package main
+
+import "testing"
+
+func TestLintSubtitlesProfile(t *testing.T) {
+	a := NewApp()
+	doc := SubtitleDocument{Language: "ru", Cues: []SubtitleCue{
+		{Index: 1, Start: 0, End: 2000, Text: "Привет"},
+		{Index: 2, Start: 3000, End: 5000, Text: "Как дела?"},
+	}}
+	tests := []struct {
+		name    string
+		profile SubtitleProfile
+		issues  int
+		wantErr bool
+	}{
+		{"zero profile uses defaults", SubtitleProfile{}, 0, false},
+		{"builtin by name", SubtitleProfile{Name: "netflix"}, 0, false},
+		{"partial custom", SubtitleProfile{Name: "custom", MaxCPS: 2}, 2, false},
+		{"negative line length", SubtitleProfile{MaxCharsPerLine: -1}, 0, true},
+		{"negative gap", SubtitleProfile{MaxCharsPerLine: 42, MaxLines: 2, MinGap: -1}, 0, true},
+		{"min above max", SubtitleProfile{MaxCharsPerLine: 42, MaxLines: 2, MinDuration: 5000, MaxDuration: 1000}, 0, true},
+	}
+	for _, tt := range tests {
+		issues, err := a.LintSubtitles(doc, tt.profile)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if len(issues) != tt.issues {
+			t.Errorf("%s: %d issues, want %d: %+v", tt.name, len(issues), tt.issues, issues)
+		}
+	}
+}
+
+func TestLintNonPositiveDuration(t *testing.T) {
+	a := NewApp()
+	doc := SubtitleDocument{Cues: []SubtitleCue{
+		{Index: 1, Start: 1000, End: 1000, Text: "Длинная реплика для чтения"},
+		{Index: 2, Start: 1500, End: 3000, Text: "Дальше"},
+	}}
+	issues, err := a.LintSubtitles(doc, subtitleProfiles["netflix"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, is := range issues {
+		if is.Rule != "non-positive-duration" {
+			continue
+		}
+		// Продление упирается в следующую реплику с учётом паузы
+		if is.Fix == nil || is.Fix.End != 1500-83 {
+			t.Errorf("fix = %+v, want end %d", is.Fix, 1500-83)
+		}
+		return
+	}
+	t.Errorf("no non-positive-duration issue in %+v", issues)
+}
+
+func TestApplyLintFixes(t *testing.T) {
+	a := NewApp()
+	doc := SubtitleDocument{Cues: []SubtitleCue{
+		{Index: 1, Start: 0, End: 1000, Text: "один"},
+		{Index: 2, Start: 2000, End: 3000, Text: "два"},
+		{Index: 3, Start: 4000, End: 5000, Text: "три"},
+	}}
+	setEnd := func(cue int, end int64) LintIssue {
+		return LintIssue{CueIndex: cue, Fix: &LintFix{Action: fixSetEnd, End: end}}
+	}
+	tests := []struct {
+		name   string
+		issues []LintIssue
+		ends   []int64
+		texts  []string
+	}{
+		{"largest extension wins", []LintIssue{setEnd(1, 1500), setEnd(1, 1800)}, []int64{1800, 3000, 5000}, nil},
+		{"limit caps extension", []LintIssue{setEnd(1, 1800), setEnd(1, 900), setEnd(1, 950)}, []int64{900, 3000, 5000}, nil},
+		{"smallest limit wins", []LintIssue{setEnd(2, 2800), setEnd(2, 2500)}, []int64{1000, 2500, 5000}, nil},
+		{"delete renumbers", []LintIssue{{CueIndex: 2, Fix: &LintFix{Action: fixDelete}}}, []int64{1000, 5000}, []string{"один", "три"}},
+		{"set text", []LintIssue{{CueIndex: 3, Fix: &LintFix{Action: fixSetText, Text: "три\nчетыре"}}}, []int64{1000, 3000, 5000}, []string{"один", "два", "три\nчетыре"}},
+		{"unknown cue and no fix ignored", []LintIssue{setEnd(9, 100), {CueIndex: 1}}, []int64{1000, 3000, 5000}, nil},
+	}
+	for _, tt := range tests {
+		got := a.ApplyLintFixes(doc, tt.issues)
+		if len(got.Cues) != len(tt.ends) {
+			t.Errorf("%s: %d cues, want %d", tt.name, len(got.Cues), len(tt.ends))
+			continue
+		}
+		for i, c := range got.Cues {
+			if c.End != tt.ends[i] || c.Index != i+1 {
+				t.Errorf("%s: cue %d = #%d end %d, want #%d end %d", tt.name, i, c.Index, c.End, i+1, tt.ends[i])
+			}
+			if tt.texts != nil && c.Text != tt.texts[i] {
+				t.Errorf("%s: cue %d text = %q, want %q", tt.name, i, c.Text, tt.texts[i])
+			}
+		}
+	}
+	if doc.Cues[0].End != 1000 {
+		t.Errorf("ApplyLintFixes modified the input document")
+	}
+}
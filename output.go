@@ -0,0 +1,222 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Политики на случай, если файл с таким именем уже существует
+const (
+	collisionOverwrite = "overwrite"
+	collisionRename    = "rename"
+	collisionSkip      = "skip"
+)
+
+// defaultFilenameTemplate сохраняет прежнее имя результата: video.mp4.srt
+const defaultFilenameTemplate = "{name}.{ext}.{format}"
+
+// ownOutputSlack запас на грубую точность времени изменения файла (2 с в FAT)
+const ownOutputSlack = 2 * time.Second
+
+// errOutputSkipped возвращается, если файл уже существует и политика — skip
+var errOutputSkipped = errors.New("Файл уже существует, сохранение пропущено")
+
+// OutputSettings настройки сохранения субтитров
+type OutputSettings struct {
+	// Directory каталог для результатов; пусто — рядом с исходным файлом
+	Directory string `json:"directory"`
+	// FilenameTemplate шаблон имени, например {name}.{lang}.{model}.{format}.
+	// Переменные: {name}, {ext}, {lang}, {model}, {format}, {date}
+	FilenameTemplate string `json:"filenameTemplate"`
+	// Collision: rename (по умолчанию), skip или overwrite. Файл, который
+	// приложение само записало и который с тех пор не менялся, обновляется
+	// при любой политике.
+	Collision string `json:"collision"`
+}
+
+// withDefaults подставляет значения по умолчанию
+func (o OutputSettings) withDefaults() OutputSettings {
+	if o.FilenameTemplate == "" {
+		o.FilenameTemplate = defaultFilenameTemplate
+	}
+	if o.Collision == "" {
+		o.Collision = collisionRename
+	}
+	return o
+}
+
+// GetOutputSettings возвращает настройки сохранения субтитров
+func (a *App) GetOutputSettings() (OutputSettings, error) {
+	settings, err := a.loadSettings()
+	if err != nil {
+		log.Printf("[GetOutputSettings] Ошибка загрузки настроек: %v\n", err)
+		return OutputSettings{}, err
+	}
+	return settings.Output.withDefaults(), nil
+}
+
+// SetOutputSettings сохраняет настройки сохранения субтитров
+func (a *App) SetOutputSettings(output OutputSettings) error {
+	log.Printf("[SetOutputSettings] %+v\n", output)
+	switch output.Collision {
+	case "", collisionOverwrite, collisionRename, collisionSkip:
+	default:
+		return errors.New("unknown collision policy")
+	}
+	if output.FilenameTemplate != "" && strings.ContainsAny(output.FilenameTemplate, `/\`) {
+		return errors.New("filename template must not contain path separators")
+	}
+	if output.Directory != "" {
+		if info, err := os.Stat(output.Directory); err != nil || !info.IsDir() {
+			return errors.New("output directory does not exist")
+		}
+	}
+	return a.updateSettings(func(settings *Settings) error {
+		settings.Output = output
+		return nil
+	})
+}
+
+// sanitizeFilenamePart убирает из значения переменной символы, недопустимые в имени файла
+func sanitizeFilenamePart(s string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// expandFilenameTemplate подставляет переменные в шаблон имени файла.
+// Пустая переменная убирается вместе с соседней точкой, точки в самом имени
+// исходного файла не трогаются.
+func expandFilenameTemplate(template, sourcePath, lang, model, format string) string {
+	const empty = "\x00"
+	value := func(s string) string {
+		if s == "" {
+			return empty
+		}
+		return s
+	}
+	base := filepath.Base(sourcePath)
+	ext := filepath.Ext(base)
+	name := strings.NewReplacer(
+		"{name}", value(strings.TrimSuffix(base, ext)),
+		"{ext}", value(strings.TrimPrefix(ext, ".")),
+		"{lang}", value(sanitizeFilenamePart(lang)),
+		"{model}", value(sanitizeFilenamePart(model)),
+		"{format}", value(sanitizeFilenamePart(format)),
+		"{date}", time.Now().Format("2006-01-02"),
+	).Replace(template)
+	return strings.NewReplacer("."+empty, "", empty+".", "", empty, "").Replace(name)
+}
+
+// writeSubtitleFile сохраняет data по настройкам вывода и возвращает путь.
+// Существующие файлы перезаписываются только при политике overwrite или
+// если это неизменённый результат прошлого запуска (см. isOwnOutput).
+func (a *App) writeSubtitleFile(sourcePath string, doc *SubtitleDocument, format string, data []byte) (string, error) {
+	settings, err := a.loadSettings()
+	if err != nil {
+		return "", err
+	}
+	output := settings.Output.withDefaults()
+	dir := output.Directory
+	if dir == "" {
+		dir = filepath.Dir(sourcePath)
+	}
+	name := expandFilenameTemplate(output.FilenameTemplate, sourcePath, doc.Language, doc.Model, format)
+	if !strings.HasSuffix(strings.ToLower(name), "."+format) {
+		name += "." + format
+	}
+	path := filepath.Join(dir, name)
+	if path == sourcePath {
+		return "", errors.New("output path matches the source file")
+	}
+	policy := output.Collision
+	if policy != collisionOverwrite && a.isOwnOutput(path) {
+		log.Printf("[writeSubtitleFile] Обновление собственного результата: %s\n", path)
+		policy = collisionOverwrite
+	}
+	return writeWithCollisionPolicy(path, data, policy)
+}
+
+// isOwnOutput сообщает, что path записан приложением при прошлом запуске
+// (есть в Outputs журнала) и с тех пор не изменялся. Решает последняя запись
+// журнала с этим путём; правленный вручную файл своим не считается.
+func (a *App) isOwnOutput(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	entries, err := a.loadHistory()
+	if err != nil {
+		log.Printf("[isOwnOutput] Ошибка чтения журнала: %v\n", err)
+		return false
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		for _, out := range entries[i].Outputs {
+			if out != path {
+				continue
+			}
+			finished := entries[i].Started.Add(time.Duration(entries[i].WallTime * float64(time.Second)))
+			return !info.ModTime().After(finished.Add(ownOutputSlack))
+		}
+	}
+	return false
+}
+
+// writeWithCollisionPolicy пишет файл, не затирая существующий без явного разрешения
+func writeWithCollisionPolicy(path string, data []byte, policy string) (string, error) {
+	if policy == collisionOverwrite {
+		return path, os.WriteFile(path, data, 0644)
+	}
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(path, ext)
+	for n := 0; n < 1000; n++ {
+		candidate := path
+		if n > 0 {
+			candidate = fmt.Sprintf("%s (%d)%s", stem, n, ext)
+		}
+		// O_EXCL гарантирует, что существующий файл не будет открыт на запись
+		f, err := os.OpenFile(candidate, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, os.ErrExist) {
+			if policy == collisionSkip {
+				return candidate, errOutputSkipped
+			}
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			return "", err
+		}
+		return candidate, f.Close()
+	}
+	return "", errors.New("too many files with the same name")
+}
+
+// SaveSubtitles сохраняет документ в формате format (см. ExportSubtitles)
+// по настройкам вывода и возвращает путь к созданному файлу
+func (a *App) SaveSubtitles(sourcePath string, doc SubtitleDocument, format string) (string, error) {
+	log.Printf("[SaveSubtitles] Сохранение субтитров для %s в формате %s\n", sourcePath, format)
+	content, err := a.ExportSubtitles(doc, format)
+	if err != nil {
+		return "", err
+	}
+	// Варианты ass-karaoke и vtt-words сохраняются с обычным расширением
+	ext := strings.SplitN(strings.ToLower(format), "-", 2)[0]
+	path, err := a.writeSubtitleFile(sourcePath, &doc, ext, []byte(content))
+	if err != nil {
+		log.Printf("[SaveSubtitles] Ошибка: %v\n", err)
+		return path, err
+	}
+	log.Printf("[SaveSubtitles] Сохранено: %s\n", path)
+	return path, nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// chdirTemp переходит во временный каталог: настройки и журнал запусков
+// лежат относительно рабочего каталога
+func chdirTemp(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return dir
+}
+
+func TestExpandFilenameTemplate(t *testing.T) {
+	tests := []struct {
+		template, source, lang, model, format string
+		want                                  string
+	}{
+		{defaultFilenameTemplate, "/v/video.mp4", "ru", "base", "srt", "video.mp4.srt"},
+		{"{name}.{lang}.{model}.{format}", "/v/video.mp4", "ru", "base", "srt", "video.ru.base.srt"},
+		{"{name}.{lang}.{model}.{format}", "/v/video.mp4", "", "base", "srt", "video.base.srt"},
+		{"{name}.{lang}.{format}", "/v/a..b.mkv", "", "", "srt", "a..b.srt"},
+		{"{name}.{model}.{format}", "/v/video.mp4", "", "a/b:c", "vtt", "video.a_b_c.vtt"},
+	}
+	for _, tt := range tests {
+		got := expandFilenameTemplate(tt.template, tt.source, tt.lang, tt.model, tt.format)
+		if got != tt.want {
+			t.Errorf("expandFilenameTemplate(%q, %q) = %q, want %q", tt.template, tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestWriteWithCollisionPolicy(t *testing.T) {
+	tests := []struct {
+		policy   string
+		wantName string
+		wantErr  error
+		wantOld  bool
+	}{
+		{collisionRename, "out (1).srt", nil, true},
+		{collisionSkip, "out.srt", errOutputSkipped, true},
+		{collisionOverwrite, "out.srt", nil, false},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "out.srt")
+		if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := writeWithCollisionPolicy(path, []byte("new"), tt.policy)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: error = %v, want %v", tt.policy, err, tt.wantErr)
+		}
+		if got != filepath.Join(dir, tt.wantName) {
+			t.Errorf("%s: path = %q, want %q", tt.policy, got, tt.wantName)
+		}
+		if old, _ := os.ReadFile(path); (string(old) == "old") != tt.wantOld {
+			t.Errorf("%s: existing file content = %q", tt.policy, old)
+		}
+	}
+}
+
+func TestWriteSubtitleFileKeepsExistingByDefault(t *testing.T) {
+	dir := chdirTemp(t)
+	a := NewApp()
+	source := filepath.Join(dir, "video.mp4")
+	existing := filepath.Join(dir, "video.mp4.srt")
+	if err := os.WriteFile(existing, []byte("hand-edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	doc := &SubtitleDocument{Language: "ru", Model: "base"}
+	path, err := a.writeSubtitleFile(source, doc, "srt", []byte("generated"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path == existing {
+		t.Fatalf("existing file was overwritten")
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "hand-edited" {
+		t.Errorf("existing file content = %q", data)
+	}
+}
+
+func TestWriteSubtitleFileUpdatesOwnOutput(t *testing.T) {
+	dir := chdirTemp(t)
+	a := NewApp()
+	source := filepath.Join(dir, "video.mp4")
+	doc := &SubtitleDocument{Language: "ru", Model: "base"}
+	path, err := a.writeSubtitleFile(source, doc, "srt", []byte("first"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := HistoryEntry{ID: "run-1", Kind: "full", Started: time.Now(), Input: source, Outputs: []string{path}}
+	data, _ := json.Marshal(entry)
+	if err := os.WriteFile(a.getHistoryPath(), append(data, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := a.writeSubtitleFile(source, doc, "srt", []byte("second"))
+	if err != nil || again != path {
+		t.Fatalf("own output: path = %q, err = %v, want %q", again, err, path)
+	}
+
+	// Правка руками после запуска делает файл чужим
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	third, err := a.writeSubtitleFile(source, doc, "srt", []byte("third"))
+	if err != nil || third == path {
+		t.Fatalf("edited output: path = %q, err = %v", third, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "second" {
+		t.Errorf("edited output content = %q", data)
+	}
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	projectExtension = ".submagic"
+	projectVersion   = 1
+	// Сколько последних проектов помнит приложение
+	maxRecentProjects = 10
+)
+
+// Статусы проверки проекта
+const (
+	reviewDraft    = "draft"
+	reviewInReview = "in-review"
+	reviewApproved = "approved"
+)
+
+// ProjectMedia ссылка на медиафайл проекта. Путь хранится и абсолютным, и
+// относительно файла проекта, чтобы папку с проектом можно было переносить.
+type ProjectMedia struct {
+	Path         string    `json:"path"`
+	RelativePath string    `json:"relativePath,omitempty"`
+	Size         int64     `json:"size"`
+	ModTime      time.Time `json:"modTime"`
+	// Missing выставляется при открытии, если файл не найден ни по одному пути
+	Missing bool `json:"missing,omitempty"`
+}
+
+// ProjectTrack дорожка субтитров проекта с историей правок
+type ProjectTrack struct {
+	ID       string           `json:"id"`
+	Name     string           `json:"name"`
+	Language string           `json:"language"`
+	Document SubtitleDocument `json:"document"`
+	Undo     []cueSplice      `json:"undo,omitempty"`
+	Redo     []cueSplice      `json:"redo,omitempty"`
+}
+
+// Project проект SubMagic: медиафайл, дорожки субтитров, использованные
+// настройки и статус проверки
+type Project struct {
+	Version      int                `json:"version"`
+	Name         string             `json:"name"`
+	Media        ProjectMedia       `json:"media"`
+	Tracks       []ProjectTrack     `json:"tracks"`
+	Transcribe   *TranscribeOptions `json:"transcribe,omitempty"`
+	Profile      *SubtitleProfile   `json:"profile,omitempty"`
+	ReviewStatus string             `json:"reviewStatus"`
+	Created      time.Time          `json:"created"`
+	Modified     time.Time          `json:"modified"`
+	// Path путь к файлу проекта; в сам файл не записывается
+	Path string `json:"path,omitempty"`
+}
+
+// RecentProject запись списка последних проектов
+type RecentProject struct {
+	Path   string `json:"path"`
+	Name   string `json:"name"`
+	Exists bool   `json:"exists"`
+}
+
+func statMedia(path string) (ProjectMedia, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return ProjectMedia{}, err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return ProjectMedia{}, err
+	}
+	return ProjectMedia{Path: abs, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// NewProject создаёт проект для медиафайла; на диск он попадает через SaveProject
+func (a *App) NewProject(mediaPath string, name string) (*Project, error) {
+	log.Printf("[NewProject] Новый проект: медиа=%s\n", mediaPath)
+	media, err := statMedia(mediaPath)
+	if err != nil {
+		log.Printf("[NewProject] Медиафайл недоступен: %v\n", err)
+		return nil, err
+	}
+	if name == "" {
+		base := filepath.Base(mediaPath)
+		name = base[:len(base)-len(filepath.Ext(base))]
+	}
+	now := time.Now()
+	return &Project{
+		Version:      projectVersion,
+		Name:         name,
+		Media:        media,
+		ReviewStatus: reviewDraft,
+		Created:      now,
+		Modified:     now,
+	}, nil
+}
+
+// SaveProject записывает проект в path (пустой — в project.Path) и
+// добавляет его в список последних
+func (a *App) SaveProject(project Project, path string) (*Project, error) {
+	if path == "" {
+		path = project.Path
+	}
+	if path == "" {
+		return nil, errors.New("no path to save project")
+	}
+	if filepath.Ext(path) != projectExtension {
+		path += projectExtension
+	}
+	log.Printf("[SaveProject] Сохранение проекта %s в %s\n", project.Name, path)
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if rel, err := filepath.Rel(filepath.Dir(abs), project.Media.Path); err == nil {
+		project.Media.RelativePath = filepath.ToSlash(rel)
+	}
+	project.Media.Missing = false
+	project.Version = projectVersion
+	project.Modified = time.Now()
+	project.Path = ""
+
+	data, err := json.MarshalIndent(project, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(abs, data, 0644); err != nil {
+		log.Printf("[SaveProject] Ошибка записи: %v\n", err)
+		return nil, err
+	}
+	project.Path = abs
+	a.addRecentProject(abs)
+	return &project, nil
+}
+
+// OpenProject читает проект. Если медиафайл переместили, он ищется по
+// относительному пути и по имени рядом с проектом; если не найден нигде,
+// выставляется Media.Missing и нужен RelinkProjectMedia.
+func (a *App) OpenProject(path string) (*Project, error) {
+	log.Printf("[OpenProject] Открытие проекта %s\n", path)
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		log.Printf("[OpenProject] Ошибка чтения: %v\n", err)
+		return nil, err
+	}
+	var project Project
+	if err := json.Unmarshal(data, &project); err != nil {
+		return nil, fmt.Errorf("invalid project file: %w", err)
+	}
+	if project.Version > projectVersion {
+		return nil, fmt.Errorf("project version %d is newer than supported %d", project.Version, projectVersion)
+	}
+	project.Path = abs
+
+	dir := filepath.Dir(abs)
+	candidates := []string{project.Media.Path}
+	if project.Media.RelativePath != "" {
+		candidates = append(candidates, filepath.Join(dir, filepath.FromSlash(project.Media.RelativePath)))
+	}
+	candidates = append(candidates, filepath.Join(dir, filepath.Base(project.Media.Path)))
+	project.Media.Missing = true
+	for _, c := range candidates {
+		if _, err := os.Stat(c); err == nil {
+			if c != project.Media.Path {
+				log.Printf("[OpenProject] Медиафайл найден по новому пути: %s\n", c)
+			}
+			project.Media.Path = c
+			project.Media.Missing = false
+			break
+		}
+	}
+	a.addRecentProject(abs)
+	return &project, nil
+}
+
+// RelinkProjectMedia указывает проекту новое расположение медиафайла
+func (a *App) RelinkProjectMedia(project Project, mediaPath string) (*Project, error) {
+	log.Printf("[RelinkProjectMedia] %s -> %s\n", project.Media.Path, mediaPath)
+	media, err := statMedia(mediaPath)
+	if err != nil {
+		return nil, err
+	}
+	if project.Media.Size > 0 && media.Size != project.Media.Size {
+		log.Printf("[RelinkProjectMedia] ПРЕДУПРЕЖДЕНИЕ: размер файла отличается (%d != %d)\n", media.Size, project.Media.Size)
+	}
+	project.Media = media
+	return &project, nil
+}
+
+// AddProjectTrack добавляет в проект дорожку субтитров
+func (a *App) AddProjectTrack(project Project, name string, doc SubtitleDocument) *Project {
+	track := ProjectTrack{
+		ID:       fmt.Sprintf("track-%d", len(project.Tracks)+1),
+		Name:     name,
+		Language: doc.Language,
+		Document: doc,
+	}
+	for findProjectTrack(&project, track.ID) >= 0 {
+		track.ID += "_"
+	}
+	project.Tracks = append(append([]ProjectTrack(nil), project.Tracks...), track)
+	return &project
+}
+
+func findProjectTrack(project *Project, trackID string) int {
+	for i, t := range project.Tracks {
+		if t.ID == trackID {
+			return i
+		}
+	}
+	return -1
+}
+
+// OpenTrackEditSession открывает дорожку проекта в редакторе вместе с её историей правок
+func (a *App) OpenTrackEditSession(project Project, trackID string) (*EditSessionState, error) {
+	i := findProjectTrack(&project, trackID)
+	if i < 0 {
+		return nil, errors.New("track not found")
+	}
+	t := project.Tracks[i]
+	return a.addSession(newEditSession(t.Document, t.Undo, t.Redo, "")), nil
+}
+
+// UpdateTrackFromSession переносит в дорожку документ и историю из сессии редактора
+func (a *App) UpdateTrackFromSession(project Project, trackID string, sessionID string) (*Project, error) {
+	i := findProjectTrack(&project, trackID)
+	if i < 0 {
+		return nil, errors.New("track not found")
+	}
+	tracks := append([]ProjectTrack(nil), project.Tracks...)
+	_, err := a.withSession(sessionID, func(s *editSession) error {
+		tracks[i].Document = s.doc
+		tracks[i].Undo = append([]cueSplice(nil), s.undo...)
+		tracks[i].Redo = append([]cueSplice(nil), s.redo...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	project.Tracks = tracks
+	return &project, nil
+}
+
+// SetProjectReviewStatus задаёт статус проверки: draft, in-review или approved
+func (a *App) SetProjectReviewStatus(project Project, status string) (*Project, error) {
+	switch status {
+	case reviewDraft, reviewInReview, reviewApproved:
+		project.ReviewStatus = status
+		return &project, nil
+	}
+	return nil, errors.New("unknown review status")
+}
+
+// ListRecentProjects возвращает последние открытые проекты, начиная с самого свежего
+func (a *App) ListRecentProjects() []RecentProject {
+	settings, err := a.loadSettings()
+	if err != nil {
+		log.Printf("[ListRecentProjects] Ошибка загрузки настроек: %v\n", err)
+		return nil
+	}
+	result := make([]RecentProject, 0, len(settings.RecentProjects))
+	for _, p := range settings.RecentProjects {
+		base := filepath.Base(p)
+		_, err := os.Stat(p)
+		result = append(result, RecentProject{Path: p, Name: base[:len(base)-len(filepath.Ext(base))], Exists: err == nil})
+	}
+	return result
+}
+
+// addRecentProject поднимает проект в начало списка последних
+func (a *App) addRecentProject(path string) {
+	err := a.updateSettings(func(settings *Settings) error {
+		recent := []string{path}
+		for _, p := range settings.RecentProjects {
+			if p != path && len(recent) < maxRecentProjects {
+				recent = append(recent, p)
+			}
+		}
+		settings.RecentProjects = recent
+		return nil
+	})
+	if err != nil {
+		log.Printf("[addRecentProject] Ошибка сохранения настроек: %v\n", err)
+	}
+}
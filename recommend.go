@@ -0,0 +1,242 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"sort"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// whisperThreads число потоков whisper-cli: без -t он берёт min(4, ядра)
+func whisperThreads() int {
+	return min(4, goruntime.NumCPU())
+}
+
+// modelFamily характеристики семейства моделей. Rtf — время обработки на
+// секунду аудио при 4 потоках, Quality — условная оценка точности (больше —
+// лучше), Overhead — память на буферы сверх весов модели.
+type modelFamily struct {
+	Prefix   string
+	Rtf      float64
+	Quality  float64
+	Overhead int64
+}
+
+// modelFamilies семейства в порядке проверки префиксов (длинные первыми).
+// Цифры приблизительные, по замерам whisper.cpp на CPU.
+var modelFamilies = []modelFamily{
+	{"large-v3-turbo", 0.30, 4.7, 700 << 20},
+	{"large-v3", 1.00, 5.0, 900 << 20},
+	{"large-v2", 1.00, 4.6, 900 << 20},
+	{"large-v1", 1.00, 4.3, 900 << 20},
+	{"distil-large-v2", 0.45, 4.2, 700 << 20},
+	{"distil-medium", 0.25, 3.5, 500 << 20},
+	{"medium", 0.50, 4.0, 600 << 20},
+	{"small", 0.18, 3.0, 400 << 20},
+	{"base", 0.06, 2.0, 250 << 20},
+	{"tiny", 0.03, 1.0, 200 << 20},
+}
+
+// SystemInfo ресурсы компьютера, от которых зависит выбор модели. Ноль —
+// значение не удалось определить.
+type SystemInfo struct {
+	CPUs            int   `json:"cpus"`
+	Threads         int   `json:"threads"`
+	AvailableMemory int64 `json:"availableMemory"`
+	FreeDisk        int64 `json:"freeDisk"`
+}
+
+// ModelRecommendation оценка одной модели для файла
+type ModelRecommendation struct {
+	Model            string   `json:"model"`
+	Downloaded       bool     `json:"downloaded"`
+	EstimatedSeconds float64  `json:"estimatedSeconds"`
+	EstimatedMemory  int64    `json:"estimatedMemory"`
+	Fits             bool     `json:"fits"`
+	Score            float64  `json:"score"`
+	Notes            []string `json:"notes,omitempty"`
+}
+
+// ModelRecommendationReport результат RecommendModel
+type ModelRecommendationReport struct {
+	System          SystemInfo            `json:"system"`
+	Duration        float64               `json:"duration"`
+	Priority        string                `json:"priority"`
+	Recommendations []ModelRecommendation `json:"recommendations"`
+}
+
+func systemInfo(modelsDir string) SystemInfo {
+	dir := modelsDir
+	if dir == "" {
+		dir = "."
+	}
+	return SystemInfo{
+		CPUs:            goruntime.NumCPU(),
+		Threads:         whisperThreads(),
+		AvailableMemory: availableMemory(),
+		FreeDisk:        freeDiskSpace(dir),
+	}
+}
+
+// findModelFamily определяет семейство по имени модели
+func findModelFamily(name string) (modelFamily, bool) {
+	for _, f := range modelFamilies {
+		if strings.HasPrefix(name, f.Prefix) {
+			return f, true
+		}
+	}
+	return modelFamily{}, false
+}
+
+// englishOnlyModel модели, обученные только на английском
+func englishOnlyModel(name string) bool {
+	return strings.Contains(name, ".en") || strings.HasPrefix(name, "distil-")
+}
+
+// estimateModelMemory оценивает пиковую память whisper-cli с моделью
+func estimateModelMemory(name string) int64 {
+	family, _ := findModelFamily(name)
+	return whisperModels[name].Size + family.Overhead
+}
+
+// estimateModelRTF оценивает время обработки секунды аудио на этом компьютере
+func estimateModelRTF(name string) float64 {
+	family, _ := findModelFamily(name)
+	rtf := family.Rtf * 4 / float64(whisperThreads())
+	// Квантованные веса читаются быстрее
+	switch {
+	case strings.Contains(name, "-q5"):
+		rtf *= 0.8
+	case strings.Contains(name, "-q8"):
+		rtf *= 0.9
+	}
+	return rtf
+}
+
+// memoryFits проверяет, что модель помещается в доступную память с запасом
+func memoryFits(required, available int64) bool {
+	return available <= 0 || float64(required) <= float64(available)*0.9
+}
+
+// RecommendModel оценивает модели для файла filePath на этом компьютере и
+// возвращает их по убыванию пригодности. priority — speed, balanced или
+// quality. Модели, которые не помещаются в память или на диск, идут в конце
+// с Fits=false.
+func (a *App) RecommendModel(filePath string, lang string, priority string) (*ModelRecommendationReport, error) {
+	log.Printf("[RecommendModel] Файл=%s, язык=%s, приоритет=%s\n", filePath, lang, priority)
+	// Вес скорости относительно точности
+	var speedWeight float64
+	switch priority {
+	case "speed":
+		speedWeight = 1.5
+	case "", "balanced":
+		priority, speedWeight = "balanced", 0.7
+	case "quality":
+		speedWeight = 0.15
+	default:
+		return nil, errors.New("unknown priority")
+	}
+	duration, err := probeMediaDuration(filePath)
+	if err != nil {
+		log.Printf("[RecommendModel] %v\n", err)
+		return nil, err
+	}
+	report := &ModelRecommendationReport{System: systemInfo(a.modelsDir), Duration: duration, Priority: priority}
+	sys := report.System
+	english := lang == "en"
+
+	for name, info := range whisperModels {
+		family, ok := findModelFamily(name)
+		if !ok || strings.HasSuffix(name, "-tdrz") {
+			// tdrz нужны только для разделения по спикерам
+			continue
+		}
+		if englishOnlyModel(name) && !english {
+			continue
+		}
+		r := ModelRecommendation{
+			Model:           name,
+			EstimatedMemory: estimateModelMemory(name),
+			Fits:            true,
+		}
+		rtf := estimateModelRTF(name)
+		r.EstimatedSeconds = math.Round(duration * rtf)
+		if _, err := os.Stat(filepath.Join(a.modelsDir, filepath.Base(info.URL))); err == nil {
+			r.Downloaded = true
+		}
+
+		quality := family.Quality
+		switch {
+		case strings.Contains(name, "-q5"):
+			quality -= 0.15
+		case strings.Contains(name, "-q8"):
+			quality -= 0.05
+		}
+		if englishOnlyModel(name) {
+			// Английские модели точнее многоязычных того же размера
+			quality += 0.3
+		}
+		// Штраф растёт с каждым удвоением времени обработки
+		r.Score = quality - speedWeight*math.Log2(1+rtf*10)
+		if r.Downloaded {
+			r.Score += 0.1
+		} else {
+			r.Notes = append(r.Notes, fmt.Sprintf("нужно скачать %d МБ", info.Size>>20))
+		}
+
+		if !memoryFits(r.EstimatedMemory, sys.AvailableMemory) {
+			r.Fits = false
+			r.Notes = append(r.Notes, fmt.Sprintf("нужно ~%d МБ памяти, доступно %d МБ", r.EstimatedMemory>>20, sys.AvailableMemory>>20))
+		}
+		if !r.Downloaded && sys.FreeDisk > 0 && info.Size > sys.FreeDisk {
+			r.Fits = false
+			r.Notes = append(r.Notes, "недостаточно места на диске")
+		}
+		if rtf > 1 {
+			r.Notes = append(r.Notes, "медленнее реального времени")
+		}
+		report.Recommendations = append(report.Recommendations, r)
+	}
+	sort.Slice(report.Recommendations, func(i, j int) bool {
+		ri, rj := report.Recommendations[i], report.Recommendations[j]
+		if ri.Fits != rj.Fits {
+			return ri.Fits
+		}
+		if ri.Score != rj.Score {
+			return ri.Score > rj.Score
+		}
+		return ri.Model < rj.Model
+	})
+	if len(report.Recommendations) > 0 {
+		best := report.Recommendations[0]
+		log.Printf("[RecommendModel] Рекомендуется %s: ~%.0f с, ~%d МБ\n", best.Model, best.EstimatedSeconds, best.EstimatedMemory>>20)
+	}
+	return report, nil
+}
+
+// checkModelMemory предупреждает, если модель не поместится в доступную
+// память. Возвращает текст предупреждения или пустую строку.
+func (a *App) checkModelMemory(name string) string {
+	required, available := estimateModelMemory(name), availableMemory()
+	if memoryFits(required, available) {
+		return ""
+	}
+	warning := fmt.Sprintf("Модели %s нужно около %d МБ памяти, а доступно %d МБ: распознавание может зависнуть или завершиться ошибкой", name, required>>20, available>>20)
+	log.Printf("[SetActiveModel] ПРЕДУПРЕЖДЕНИЕ: %s\n", warning)
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "modelMemoryWarning", map[string]interface{}{
+			"name":            name,
+			"requiredMemory":  required,
+			"availableMemory": available,
+			"message":         warning,
+		})
+	}
+	return warning
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// SubtitleProfile требования к оформлению субтитров (вещательный стандарт
+// или требования клиента). Время — в миллисекундах.
+type SubtitleProfile struct {
+	Name            string  `json:"name"`
+	MaxCharsPerLine int     `json:"maxCharsPerLine"`
+	MaxLines        int     `json:"maxLines"`
+	MinDuration     int64   `json:"minDuration"`
+	MaxDuration     int64   `json:"maxDuration"`
+	MaxCPS          float64 `json:"maxCps"` // символов в секунду
+	MinGap          int64   `json:"minGap"` // минимальная пауза между репликами
+}
+
+// subtitleProfiles встроенные профили. netflix — по Timed Text Style Guide
+// (42 символа, 20 симв/с, пауза 2 кадра), bbc — по BBC Subtitle Guidelines.
+var subtitleProfiles = map[string]SubtitleProfile{
+	"netflix": {Name: "netflix", MaxCharsPerLine: 42, MaxLines: 2, MinDuration: 833, MaxDuration: 7000, MaxCPS: 20, MinGap: 83},
+	"bbc":     {Name: "bbc", MaxCharsPerLine: 37, MaxLines: 2, MinDuration: 1000, MaxDuration: 7000, MaxCPS: 17, MinGap: 80},
+	"social":  {Name: "social", MaxCharsPerLine: 24, MaxLines: 1, MinDuration: 400, MaxDuration: 3000, MaxCPS: 25, MinGap: 0},
+}
+
+// ListSubtitleProfiles возвращает встроенные профили оформления
+func (a *App) ListSubtitleProfiles() []SubtitleProfile {
+	result := make([]SubtitleProfile, 0, len(subtitleProfiles))
+	for _, name := range []string{"netflix", "bbc", "social"} {
+		result = append(result, subtitleProfiles[name])
+	}
+	return result
+}
+
+// withDefaults дополняет профиль, пришедший из фронтенда. Профиль с именем
+// встроенного берёт из него все незаданные поля. У остальных нулевые
+// MaxDuration и MaxCPS означают «без ограничения», а длина и число строк
+// берутся из netflix.
+func (p SubtitleProfile) withDefaults() SubtitleProfile {
+	base, builtin := subtitleProfiles[p.Name]
+	if !builtin {
+		base = subtitleProfiles["netflix"]
+	}
+	if p.MaxCharsPerLine == 0 {
+		p.MaxCharsPerLine = base.MaxCharsPerLine
+	}
+	if p.MaxLines == 0 {
+		p.MaxLines = base.MaxLines
+	}
+	if builtin {
+		if p.MinDuration == 0 {
+			p.MinDuration = base.MinDuration
+		}
+		if p.MaxDuration == 0 {
+			p.MaxDuration = base.MaxDuration
+		}
+		if p.MaxCPS == 0 {
+			p.MaxCPS = base.MaxCPS
+		}
+		if p.MinGap == 0 {
+			p.MinGap = base.MinGap
+		}
+	}
+	return p
+}
+
+// validate проверяет, что профиль пригоден для переразбивки и проверки
+func (p *SubtitleProfile) validate() error {
+	if p.MaxCharsPerLine <= 0 || p.MaxLines <= 0 {
+		return errors.New("profile: maxCharsPerLine and maxLines must be positive")
+	}
+	if p.MinDuration < 0 || p.MaxDuration < 0 || p.MaxCPS < 0 || p.MinGap < 0 {
+		return errors.New("profile: durations, gap and maxCps must not be negative")
+	}
+	if p.MaxDuration > 0 && p.MinDuration > p.MaxDuration {
+		return errors.New("profile: minDuration exceeds maxDuration")
+	}
+	return nil
+}
+
+// ReflowSubtitles переразбивает реплики под профиль: режет длинные реплики
+// по пунктуации и границам слов, склеивает слишком короткие, расставляет
+// переносы строк и подгоняет время под длительность, скорость чтения и паузы
+func (a *App) ReflowSubtitles(doc SubtitleDocument, profile SubtitleProfile) (*SubtitleDocument, error) {
+	log.Printf("[ReflowSubtitles] Переразбивка %d реплик по профилю %s\n", len(doc.Cues), profile.Name)
+	profile = profile.withDefaults()
+	if err := profile.validate(); err != nil {
+		return nil, err
+	}
+	var cues []SubtitleCue
+	for _, c := range doc.Cues {
+		cues = append(cues, splitCue(c, &profile)...)
+	}
+	cues = mergeShortCues(cues, &profile)
+	for i := range cues {
+		cues[i].Text = wrapText(flattenText(cues[i].Text), profile.MaxCharsPerLine, profile.MaxLines)
+	}
+	retimeCues(cues, &profile)
+	renumberCues(cues)
+	doc.Cues = cues
+	log.Printf("[ReflowSubtitles] Получилось %d реплик\n", len(cues))
+	return &doc, nil
+}
+
+// reflowToken слово реплики с временем; без пословных меток время
+// распределяется пропорционально длине слов
+type reflowToken struct {
+	word  Word
+	timed bool
+}
+
+func cueTokens(c SubtitleCue) []reflowToken {
+	if len(c.Words) > 0 {
+		tokens := make([]reflowToken, len(c.Words))
+		for i, w := range c.Words {
+			tokens[i] = reflowToken{word: w, timed: true}
+		}
+		return tokens
+	}
+	fields := strings.Fields(c.Text)
+	total := 0
+	for _, f := range fields {
+		total += utf8.RuneCountInString(f) + 1
+	}
+	var tokens []reflowToken
+	pos := 0
+	dur := float64(c.End - c.Start)
+	for _, f := range fields {
+		n := utf8.RuneCountInString(f) + 1
+		start := c.Start + int64(dur*float64(pos)/float64(total))
+		end := c.Start + int64(dur*float64(pos+n)/float64(total))
+		tokens = append(tokens, reflowToken{word: Word{Text: f, Start: start, End: end, Confidence: c.Confidence}})
+		pos += n
+	}
+	return tokens
+}
+
+func flattenText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// endsSentence и endsClause определяют предпочтительные места разреза
+func endsSentence(w string) bool {
+	return strings.ContainsAny(lastRune(w), ".!?…")
+}
+
+func endsClause(w string) bool {
+	return strings.ContainsAny(lastRune(w), ",;:—-")
+}
+
+func lastRune(s string) string {
+	s = strings.TrimRight(s, `"»)'`)
+	if s == "" {
+		return ""
+	}
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return string(r)
+}
+
+// fitsProfile проверяет, помещается ли текст в допустимое число строк
+func fitsProfile(text string, p *SubtitleProfile) bool {
+	return len(strings.Split(wrapText(text, p.MaxCharsPerLine, 0), "\n")) <= p.MaxLines
+}
+
+// splitCue режет реплику на части, каждая из которых помещается в профиль.
+// Разрез предпочтительно ставится после конца предложения или фразы.
+func splitCue(c SubtitleCue, p *SubtitleProfile) []SubtitleCue {
+	text := flattenText(c.Text)
+	if fitsProfile(text, p) && (p.MaxDuration <= 0 || c.End-c.Start <= p.MaxDuration) {
+		return []SubtitleCue{c}
+	}
+	tokens := cueTokens(c)
+	if len(tokens) == 0 {
+		return []SubtitleCue{c}
+	}
+	capacity := p.MaxCharsPerLine * p.MaxLines
+
+	var result []SubtitleCue
+	var cur []reflowToken
+	closeChunk := func() {
+		if len(cur) > 0 {
+			result = append(result, cueFromTokens(c, cur))
+			cur = nil
+		}
+	}
+	for _, t := range tokens {
+		if len(cur) > 0 {
+			candidate := tokensText(append(cur[:len(cur):len(cur)], t))
+			tooLong := p.MaxDuration > 0 && t.word.End-cur[0].word.Start > p.MaxDuration
+			if !fitsProfile(candidate, p) || tooLong {
+				closeChunk()
+			}
+		}
+		cur = append(cur, t)
+		n := utf8.RuneCountInString(tokensText(cur))
+		if (endsSentence(t.word.Text) && n*3 >= capacity) || (endsClause(t.word.Text) && n*5 >= capacity*3) {
+			closeChunk()
+		}
+	}
+	closeChunk()
+	return result
+}
+
+func tokensText(tokens []reflowToken) string {
+	words := make([]string, len(tokens))
+	for i, t := range tokens {
+		words[i] = t.word.Text
+	}
+	return strings.Join(words, " ")
+}
+
+// cueFromTokens собирает новую реплику из части слов исходной
+func cueFromTokens(orig SubtitleCue, tokens []reflowToken) SubtitleCue {
+	c := SubtitleCue{
+		Start:      tokens[0].word.Start,
+		End:        tokens[len(tokens)-1].word.End,
+		Text:       tokensText(tokens),
+		Speaker:    orig.Speaker,
+		Confidence: orig.Confidence,
+		Review:     orig.Review,
+	}
+	if tokens[0].timed {
+		for _, t := range tokens {
+			c.Words = append(c.Words, t.word)
+		}
+	}
+	return c
+}
+
+// maxMergeGap пауза, больше которой реплики не склеиваются даже если они короткие
+const maxMergeGap = 1000
+
+// mergeShortCues склеивает слишком короткие реплики со следующими, если
+// результат помещается в профиль и говорит тот же спикер
+func mergeShortCues(cues []SubtitleCue, p *SubtitleProfile) []SubtitleCue {
+	var result []SubtitleCue
+	for _, c := range cues {
+		if n := len(result); n > 0 {
+			prev := &result[n-1]
+			short := prev.End-prev.Start < p.MinDuration || c.End-c.Start < p.MinDuration
+			text := flattenText(prev.Text + " " + c.Text)
+			if short && prev.Speaker == c.Speaker && c.Start-prev.End <= maxMergeGap &&
+				fitsProfile(text, p) && (p.MaxDuration <= 0 || c.End-prev.Start <= p.MaxDuration) {
+				prev.Text = text
+				prev.End = c.End
+				prev.Words = append(prev.Words, c.Words...)
+				prev.Review = prev.Review || c.Review
+				if c.Confidence > 0 && (prev.Confidence == 0 || c.Confidence < prev.Confidence) {
+					prev.Confidence = c.Confidence
+				}
+				continue
+			}
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// wrapText разбивает текст на строки не длиннее maxChars. Если получается
+// две строки, место переноса выбирается так, чтобы строки были близки по
+// длине, с предпочтением переноса после знаков препинания. maxLines <= 0
+// снимает ограничение на число строк при балансировке.
+func wrapText(text string, maxChars, maxLines int) string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(strings.Join(words, " ")) <= maxChars {
+		return strings.Join(words, " ")
+	}
+
+	var lines []string
+	line := ""
+	for _, w := range words {
+		if line != "" && utf8.RuneCountInString(line+" "+w) > maxChars {
+			lines = append(lines, line)
+			line = w
+		} else if line == "" {
+			line = w
+		} else {
+			line += " " + w
+		}
+	}
+	lines = append(lines, line)
+
+	if len(lines) == 2 && maxLines != 1 {
+		best := -1
+		bestScore := math.Inf(1)
+		for i := 1; i < len(words); i++ {
+			first := strings.Join(words[:i], " ")
+			second := strings.Join(words[i:], " ")
+			l1, l2 := utf8.RuneCountInString(first), utf8.RuneCountInString(second)
+			if l1 > maxChars || l2 > maxChars {
+				continue
+			}
+			score := math.Abs(float64(l1 - l2))
+			if endsSentence(words[i-1]) || endsClause(words[i-1]) {
+				score -= float64(maxChars) / 4
+			}
+			if score < bestScore {
+				bestScore = score
+				best = i
+			}
+		}
+		if best > 0 {
+			return strings.Join(words[:best], " ") + "\n" + strings.Join(words[best:], " ")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// readingDuration минимальная длительность показа текста при заданной скорости чтения
+func readingDuration(text string, cps float64) int64 {
+	if cps <= 0 {
+		return 0
+	}
+	n := utf8.RuneCountInString(strings.ReplaceAll(text, "\n", ""))
+	return int64(math.Ceil(float64(n) / cps * 1000))
+}
+
+// retimeCues продлевает реплики до минимальной длительности и скорости чтения,
+// не залезая на следующую реплику, и выдерживает минимальную паузу между ними
+func retimeCues(cues []SubtitleCue, p *SubtitleProfile) {
+	for i := range cues {
+		c := &cues[i]
+		need := max(p.MinDuration, readingDuration(c.Text, p.MaxCPS))
+		if p.MaxDuration > 0 {
+			need = min(need, p.MaxDuration)
+		}
+		limit := int64(math.MaxInt64)
+		if i+1 < len(cues) {
+			limit = cues[i+1].Start - p.MinGap
+		}
+		if c.End-c.Start < need {
+			c.End = max(c.End, min(c.Start+need, limit))
+		}
+		if p.MaxDuration > 0 && c.End-c.Start > p.MaxDuration {
+			c.End = c.Start + p.MaxDuration
+		}
+		if c.End > limit && limit > c.Start {
+			c.End = limit
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		text     string
+		maxChars int
+		maxLines int
+		want     string
+	}{
+		{"short text", 42, 2, "short text"},
+		{"  a   b\n c ", 42, 2, "a b c"},
+		{"", 42, 2, ""},
+		// Две строки балансируются по длине, а не заполняются жадно
+		{"aaaa bbbb cccc dddd eeee", 20, 2, "aaaa bbbb\ncccc dddd eeee"},
+		// Перенос после запятой предпочтительнее
+		{"Да, конечно, я приду завтра утром", 20, 2, "Да, конечно,\nя приду завтра утром"},
+		// Слово длиннее строки не режется
+		{"сверхдлинноесловобольшедвадцати ok", 20, 2, "сверхдлинноесловобольшедвадцати\nok"},
+		// При одной строке балансировка не нужна
+		{"aaaa bbbb cccc dddd eeee", 20, 1, "aaaa bbbb cccc dddd\neeee"},
+		{"aaaa bbbb cccc dddd eeee ffff gggg", 10, 2, "aaaa bbbb\ncccc dddd\neeee ffff\ngggg"},
+	}
+	for _, tt := range tests {
+		if got := wrapText(tt.text, tt.maxChars, tt.maxLines); got != tt.want {
+			t.Errorf("wrapText(%q, %d, %d) = %q, want %q", tt.text, tt.maxChars, tt.maxLines, got, tt.want)
+		}
+	}
+}
+
+func TestReadingDuration(t *testing.T) {
+	tests := []struct {
+		text string
+		cps  float64
+		want int64
+	}{
+		{"12345", 10, 500},
+		{"ab\ncd", 2, 2000},
+		{"abc", 7, 429},
+		{"abc", 0, 0},
+	}
+	for _, tt := range tests {
+		if got := readingDuration(tt.text, tt.cps); got != tt.want {
+			t.Errorf("readingDuration(%q, %v) = %d, want %d", tt.text, tt.cps, got, tt.want)
+		}
+	}
+}
+
+func TestSplitCue(t *testing.T) {
+	p := subtitleProfiles["social"]
+	tests := []struct {
+		name string
+		cue  SubtitleCue
+	}{
+		{"fits", SubtitleCue{Start: 0, End: 2000, Text: "Привет всем"}},
+		{"long text", SubtitleCue{Start: 0, End: 6000, Text: "Привет всем. Сегодня мы поговорим о субтитрах и их оформлении"}},
+		{"long duration", SubtitleCue{Start: 1000, End: 9000, Text: "Очень медленная речь"}},
+		{"word timings", SubtitleCue{Start: 0, End: 4000, Text: "раз два три четыре пять шесть семь", Words: []Word{
+			{Text: "раз", Start: 0, End: 500}, {Text: "два", Start: 500, End: 1000}, {Text: "три", Start: 1000, End: 1500},
+			{Text: "четыре", Start: 1500, End: 2000}, {Text: "пять", Start: 2000, End: 2500}, {Text: "шесть", Start: 2500, End: 3200},
+			{Text: "семь", Start: 3200, End: 4000},
+		}}},
+	}
+	for _, tt := range tests {
+		parts := splitCue(tt.cue, &p)
+		var texts []string
+		prevEnd := tt.cue.Start
+		for _, c := range parts {
+			texts = append(texts, c.Text)
+			if !fitsProfile(c.Text, &p) {
+				t.Errorf("%s: part %q does not fit the profile", tt.name, c.Text)
+			}
+			if c.Start < prevEnd || c.End < c.Start || c.End > tt.cue.End {
+				t.Errorf("%s: part %q has bad timing %d-%d", tt.name, c.Text, c.Start, c.End)
+			}
+			prevEnd = c.End
+		}
+		if got := strings.Join(texts, " "); got != flattenText(tt.cue.Text) {
+			t.Errorf("%s: parts join to %q, want %q", tt.name, got, tt.cue.Text)
+		}
+	}
+	if parts := splitCue(tests[2].cue, &p); len(parts) < 2 {
+		t.Errorf("long duration: not split, got %d parts", len(parts))
+	}
+}
+
+func TestMergeShortCues(t *testing.T) {
+	p := subtitleProfiles["netflix"]
+	tests := []struct {
+		name string
+		cues []SubtitleCue
+		want []string
+	}{
+		{"short merged", []SubtitleCue{{Start: 0, End: 300, Text: "Да."}, {Start: 400, End: 1500, Text: "Конечно."}}, []string{"Да. Конечно."}},
+		{"long kept", []SubtitleCue{{Start: 0, End: 2000, Text: "Да."}, {Start: 2100, End: 4000, Text: "Конечно."}}, []string{"Да.", "Конечно."}},
+		{"other speaker", []SubtitleCue{{Start: 0, End: 300, Text: "Да.", Speaker: "S1"}, {Start: 400, End: 1500, Text: "Нет.", Speaker: "S2"}}, []string{"Да.", "Нет."}},
+		{"long gap", []SubtitleCue{{Start: 0, End: 300, Text: "Да."}, {Start: 2000, End: 3000, Text: "Конечно."}}, []string{"Да.", "Конечно."}},
+		{"too long together", []SubtitleCue{
+			{Start: 0, End: 300, Text: strings.Repeat("слово ", 10)},
+			{Start: 400, End: 1500, Text: strings.Repeat("другое ", 10)},
+		}, []string{flattenText(strings.Repeat("слово ", 10)), flattenText(strings.Repeat("другое ", 10))}},
+	}
+	for _, tt := range tests {
+		got := mergeShortCues(tt.cues, &p)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: %d cues, want %d", tt.name, len(got), len(tt.want))
+			continue
+		}
+		for i, c := range got {
+			if flattenText(c.Text) != tt.want[i] {
+				t.Errorf("%s: cue %d = %q, want %q", tt.name, i, c.Text, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestRetimeCues(t *testing.T) {
+	p := SubtitleProfile{MaxCharsPerLine: 42, MaxLines: 2, MinDuration: 1000, MaxDuration: 5000, MaxCPS: 10, MinGap: 100}
+	tests := []struct {
+		name string
+		cues []SubtitleCue
+		ends []int64
+	}{
+		{"min duration", []SubtitleCue{{Start: 0, End: 200, Text: "Да"}}, []int64{1000}},
+		{"reading speed", []SubtitleCue{{Start: 0, End: 1000, Text: strings.Repeat("а", 20)}}, []int64{2000}},
+		{"stops before next", []SubtitleCue{{Start: 0, End: 200, Text: "Да"}, {Start: 600, End: 2000, Text: "Нет"}}, []int64{500, 2000}},
+		{"max duration", []SubtitleCue{{Start: 0, End: 9000, Text: "Да"}}, []int64{5000}},
+		{"overlap trimmed", []SubtitleCue{{Start: 0, End: 3000, Text: "Да"}, {Start: 2000, End: 4000, Text: "Нет"}}, []int64{1900, 4000}},
+	}
+	for _, tt := range tests {
+		retimeCues(tt.cues, &p)
+		for i, c := range tt.cues {
+			if c.End != tt.ends[i] {
+				t.Errorf("%s: cue %d end = %d, want %d", tt.name, i, c.End, tt.ends[i])
+			}
+		}
+	}
+}
+
+func TestReflowSubtitles(t *testing.T) {
+	a := NewApp()
+	doc := SubtitleDocument{Cues: []SubtitleCue{
+		{Index: 1, Start: 0, End: 8000, Text: "Это очень длинная реплика, которую нужно разбить на несколько частей, потому что она не помещается в две строки."},
+		{Index: 2, Start: 9000, End: 9200, Text: "Ок."},
+	}}
+	got, err := a.ReflowSubtitles(doc, SubtitleProfile{Name: "netflix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := subtitleProfiles["netflix"]
+	for i, c := range got.Cues {
+		if c.Index != i+1 {
+			t.Errorf("cue %d has index %d", i, c.Index)
+		}
+		for _, line := range strings.Split(c.Text, "\n") {
+			if utf8.RuneCountInString(line) > p.MaxCharsPerLine {
+				t.Errorf("cue %d line %q is longer than %d", c.Index, line, p.MaxCharsPerLine)
+			}
+		}
+		if len(strings.Split(c.Text, "\n")) > p.MaxLines {
+			t.Errorf("cue %d has more than %d lines: %q", c.Index, p.MaxLines, c.Text)
+		}
+		if c.End-c.Start < p.MinDuration {
+			t.Errorf("cue %d is shorter than %d ms: %d-%d", c.Index, p.MinDuration, c.Start, c.End)
+		}
+	}
+	if len(got.Cues) < 2 {
+		t.Errorf("long cue was not split: %+v", got.Cues)
+	}
+	if _, err := a.ReflowSubtitles(doc, SubtitleProfile{MaxCharsPerLine: -5}); err == nil {
+		t.Errorf("invalid profile accepted")
+	}
+}
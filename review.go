@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"log"
+)
+
+// defaultConfidenceThreshold порог уверенности, ниже которого реплика считается сомнительной
+const defaultConfidenceThreshold = 0.6
+
+// GetLowConfidenceCues возвращает реплики с уверенностью ниже threshold
+// (0 — порог по умолчанию). Реплики без оценки уверенности пропускаются.
+func (a *App) GetLowConfidenceCues(doc SubtitleDocument, threshold float64) []SubtitleCue {
+	if threshold <= 0 {
+		threshold = defaultConfidenceThreshold
+	}
+	var result []SubtitleCue
+	for _, c := range doc.Cues {
+		if c.Confidence > 0 && c.Confidence < threshold {
+			result = append(result, c)
+		}
+	}
+	log.Printf("[GetLowConfidenceCues] Порог %.2f: %d из %d реплик\n", threshold, len(result), len(doc.Cues))
+	return result
+}
+
+// FlagLowConfidenceCues помечает для проверки все реплики с уверенностью ниже threshold
+func (a *App) FlagLowConfidenceCues(doc SubtitleDocument, threshold float64) *SubtitleDocument {
+	low := map[int]bool{}
+	for _, c := range a.GetLowConfidenceCues(doc, threshold) {
+		low[c.Index] = true
+	}
+	cues := make([]SubtitleCue, len(doc.Cues))
+	copy(cues, doc.Cues)
+	for i := range cues {
+		if low[cues[i].Index] {
+			cues[i].Review = true
+		}
+	}
+	doc.Cues = cues
+	return &doc
+}
+
+// SetCueReview ставит или снимает отметку проверки у реплики с номером index
+func (a *App) SetCueReview(doc SubtitleDocument, index int, review bool) (*SubtitleDocument, error) {
+	cues := make([]SubtitleCue, len(doc.Cues))
+	copy(cues, doc.Cues)
+	for i := range cues {
+		if cues[i].Index == index {
+			cues[i].Review = review
+			doc.Cues = cues
+			return &doc, nil
+		}
+	}
+	return nil, errors.New("cue not found")
+}
@@ -0,0 +1,33 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+)
+
+// runMeasured выполняет команду и возвращает её объединённый вывод и пиковую
+// память процесса в байтах
+func runMeasured(cmd *exec.Cmd) ([]byte, int64, error) {
+	out, err := cmd.CombinedOutput()
+	return out, peakMemory(cmd.ProcessState), err
+}
+
+// peakMemory возвращает пиковый RSS завершившегося процесса в байтах
+func peakMemory(state *os.ProcessState) int64 {
+	if state == nil {
+		return 0
+	}
+	usage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return 0
+	}
+	// macOS сообщает ru_maxrss в байтах, Linux и BSD — в килобайтах
+	if runtime.GOOS == "darwin" {
+		return int64(usage.Maxrss)
+	}
+	return int64(usage.Maxrss) * 1024
+}
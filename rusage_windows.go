@@ -0,0 +1,52 @@
+//go:build windows
+
+package main
+
+import (
+	"bytes"
+	"os/exec"
+	"syscall"
+	"unsafe"
+)
+
+const processQueryLimitedInformation = 0x1000
+
+var procGetProcessMemoryInfo = syscall.NewLazyDLL("kernel32.dll").NewProc("K32GetProcessMemoryInfo")
+
+// processMemoryCounters структура PROCESS_MEMORY_COUNTERS из psapi.h
+type processMemoryCounters struct {
+	Cb                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
+}
+
+// runMeasured выполняет команду и возвращает её объединённый вывод и пиковый
+// рабочий набор процесса в байтах. os.ProcessState на Windows не содержит
+// памяти, поэтому она читается через собственный дескриптор процесса: он не
+// даёт объекту процесса исчезнуть после Wait.
+func runMeasured(cmd *exec.Cmd) ([]byte, int64, error) {
+	var out bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &out, &out
+	if err := cmd.Start(); err != nil {
+		return nil, 0, err
+	}
+	h, herr := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(cmd.Process.Pid))
+	err := cmd.Wait()
+	if herr != nil {
+		return out.Bytes(), 0, err
+	}
+	defer syscall.CloseHandle(h)
+	var c processMemoryCounters
+	c.Cb = uint32(unsafe.Sizeof(c))
+	if r, _, _ := procGetProcessMemoryInfo.Call(uintptr(h), uintptr(unsafe.Pointer(&c)), uintptr(c.Cb)); r == 0 {
+		return out.Bytes(), 0, err
+	}
+	return out.Bytes(), int64(c.PeakWorkingSetSize), err
+}
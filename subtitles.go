@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SubtitleCue одна реплика субтитров. Время хранится в миллисекундах,
+// чтобы фронтенд получал простые числа, а не time.Duration.
+type SubtitleCue struct {
+	Index   int    `json:"index"`
+	Start   int64  `json:"start"`
+	End     int64  `json:"end"`
+	Text    string `json:"text"`
+	Speaker string `json:"speaker,omitempty"` // идентификатор из SubtitleDocument.Speakers
+	Words   []Word `json:"words,omitempty"`   // пословные метки, если они были запрошены
+	// Confidence средняя вероятность токенов реплики 0..1, 0 — неизвестна
+	Confidence float64 `json:"confidence,omitempty"`
+	// Review помечает реплику для проверки редактором
+	Review bool `json:"review,omitempty"`
+	// Style имя стиля ASS из SubtitleDocument.Styles
+	Style string `json:"style,omitempty"`
+}
+
+// Word слово с собственными метками времени (мс) и уверенностью модели 0..1
+type Word struct {
+	Text       string  `json:"text"`
+	Start      int64   `json:"start"`
+	End        int64   `json:"end"`
+	Confidence float64 `json:"confidence"`
+}
+
+// SubtitleDocument набор реплик одной дорожки субтитров
+type SubtitleDocument struct {
+	Language string        `json:"language"`
+	Model    string        `json:"model,omitempty"`
+	Cues     []SubtitleCue `json:"cues"`
+	// Speakers отображает идентификатор спикера (S1, S2, ...) в имя для вывода
+	Speakers map[string]string `json:"speakers,omitempty"`
+	// Styles стили импортированного ASS-файла, сохраняются для экспорта в ASS
+	Styles *StyleSheet `json:"styles,omitempty"`
+}
+
+// StyleSheet раздел стилей ASS в исходном виде: строка Format и строки
+// Style в порядке полей этого Format
+type StyleSheet struct {
+	PlayResX int      `json:"playResX,omitempty"`
+	PlayResY int      `json:"playResY,omitempty"`
+	Format   string   `json:"format"`
+	Styles   []string `json:"styles"`
+}
+
+// names возвращает имена стилей
+func (s *StyleSheet) names() map[string]bool {
+	names := map[string]bool{}
+	field := 0
+	for i, f := range strings.Split(strings.TrimPrefix(s.Format, "Format:"), ",") {
+		if strings.EqualFold(strings.TrimSpace(f), "Name") {
+			field = i
+		}
+	}
+	for _, line := range s.Styles {
+		parts := strings.Split(strings.TrimPrefix(line, "Style:"), ",")
+		if field < len(parts) {
+			names[strings.TrimSpace(parts[field])] = true
+		}
+	}
+	return names
+}
+
+// speakerName возвращает имя спикера реплики или пустую строку
+func (d *SubtitleDocument) speakerName(c SubtitleCue) string {
+	if c.Speaker == "" {
+		return ""
+	}
+	if name, ok := d.Speakers[c.Speaker]; ok && name != "" {
+		return name
+	}
+	return c.Speaker
+}
+
+// parseSRTTimestamp разбирает метку вида 00:01:02,345 в миллисекунды.
+// Дробная часть может быть короче трёх цифр (1,5 — это 1500 мс).
+func parseSRTTimestamp(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	clock, frac, _ := strings.Cut(strings.Replace(s, ".", ",", 1), ",")
+	var h, m, sec int64
+	var rest string
+	if n, _ := fmt.Sscanf(clock, "%d:%d:%d%s", &h, &m, &sec, &rest); n != 3 {
+		return 0, fmt.Errorf("invalid SRT timestamp %q", s)
+	}
+	if len(frac) > 3 {
+		return 0, fmt.Errorf("invalid SRT timestamp %q: fraction longer than milliseconds", s)
+	}
+	var ms int64
+	if frac != "" {
+		v, err := strconv.Atoi((frac + "00")[:3])
+		if err != nil || strings.ContainsAny(frac, "+-") {
+			return 0, fmt.Errorf("invalid SRT timestamp %q", s)
+		}
+		ms = int64(v)
+	}
+	return ((h*60+m)*60+sec)*1000 + ms, nil
+}
+
+// formatSRTTimestamp форматирует миллисекунды в метку SRT
+func formatSRTTimestamp(ms int64) string {
+	if ms < 0 {
+		ms = 0
+	}
+	h := ms / 3600000
+	m := ms / 60000 % 60
+	s := ms / 1000 % 60
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", h, m, s, ms%1000)
+}
+
+// parseSRT разбирает содержимое SRT-файла в список реплик
+func parseSRT(data string) ([]SubtitleCue, error) {
+	var cues []SubtitleCue
+	scanner := bufio.NewScanner(strings.NewReader(strings.TrimPrefix(data, "\ufeff")))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var cur *SubtitleCue
+	var text []string
+	flush := func() {
+		if cur != nil {
+			cur.Text = strings.Join(text, "\n")
+			cues = append(cues, *cur)
+		}
+		cur = nil
+		text = nil
+	}
+
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+		case cur == nil && strings.Contains(line, "-->"):
+			parts := strings.SplitN(line, "-->", 2)
+			start, err := parseSRTTimestamp(parts[0])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			// После конечной метки могут идти координаты, отбрасываем их
+			endField := strings.Fields(parts[1])
+			if len(endField) == 0 {
+				return nil, fmt.Errorf("line %d: missing end timestamp", lineNo)
+			}
+			end, err := parseSRTTimestamp(endField[0])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			cur = &SubtitleCue{Index: len(cues) + 1, Start: start, End: end}
+		case cur == nil:
+			// Номер реплики: игнорируем, нумерация пересчитывается
+			if _, err := strconv.Atoi(strings.TrimSpace(line)); err != nil {
+				return nil, fmt.Errorf("line %d: unexpected text %q", lineNo, line)
+			}
+		default:
+			text = append(text, strings.TrimSpace(line))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return cues, nil
+}
+
+// formatSRT сериализует реплики в формат SRT с перенумерацией
+func formatSRT(cues []SubtitleCue) string {
+	var b strings.Builder
+	for i, c := range cues {
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1, formatSRTTimestamp(c.Start), formatSRTTimestamp(c.End), c.Text)
+	}
+	return b.String()
+}
+
+// shiftCues сдвигает все реплики на offset миллисекунд
+func shiftCues(cues []SubtitleCue, offset int64) {
+	for i := range cues {
+		cues[i].Start += offset
+		cues[i].End += offset
+		for j := range cues[i].Words {
+			cues[i].Words[j].Start += offset
+			cues[i].Words[j].End += offset
+		}
+	}
+}
+
+// renumberCues восстанавливает последовательную нумерацию реплик
+func renumberCues(cues []SubtitleCue) {
+	for i := range cues {
+		cues[i].Index = i + 1
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import "testing"
+
+func TestParseSRTTimestamp(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"00:00:01,000", 1000, false},
+		{"01:02:03,004", 3723004, false},
+		{"00:00:01.500", 1500, false},
+		{"00:00:01.5", 1500, false},
+		{"00:00:01,05", 1050, false},
+		{" 00:00:02,250 ", 2250, false},
+		{"00:00:01", 1000, false},
+		{"00:00:01.1234", 0, true},
+		{"00:00:01,x", 0, true},
+		{"00:01", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSRTTimestamp(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSRTTimestamp(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseSRTTimestamp(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatSRTTimestamp(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{0, "00:00:00,000"},
+		{1500, "00:00:01,500"},
+		{3723004, "01:02:03,004"},
+		{-10, "00:00:00,000"},
+	}
+	for _, tt := range tests {
+		if got := formatSRTTimestamp(tt.in); got != tt.want {
+			t.Errorf("formatSRTTimestamp(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// syncModelPreference модели для распознавания при синхронизации: текст нужен
+// только как ориентир, поэтому берём первую скачанную из небольших
+var syncModelPreference = []string{"base", "base-q8_0", "base-q5_1", "small-q5_1", "small-q8_0", "small", "tiny", "tiny-q8_0", "tiny-q5_1", "large-v3-turbo-q5_0"}
+
+// CueSync результат синхронизации одной реплики
+type CueSync struct {
+	CueIndex int    `json:"cueIndex"`
+	Offset   int64  `json:"offset"`  // на сколько мс сдвинута реплика
+	Matched  int    `json:"matched"` // сколько слов реплики найдено в распознанном тексте
+	Words    int    `json:"words"`
+	Method   string `json:"method"` // matched — по своим словам, interpolated — по соседям
+}
+
+// SyncReport результат SyncSubtitles
+type SyncReport struct {
+	OutputPath string            `json:"outputPath"`
+	Document   *SubtitleDocument `json:"document"`
+	Cues       []CueSync         `json:"cues"`
+	Model      string            `json:"model"`
+}
+
+// pickLocalModel возвращает первую скачанную модель из списка
+func (a *App) pickLocalModel(preference []string) (string, error) {
+	for _, name := range preference {
+		if _, err := a.resolveModelPath(name); err == nil {
+			return name, nil
+		}
+	}
+	return "", errors.New("Нет подходящей скачанной модели. Скачайте, например, base в настройках.")
+}
+
+// cueWord слово исходной реплики с оценкой его времени внутри реплики
+type cueWord struct {
+	cue  int
+	norm string
+	at   int64
+}
+
+// splitCueWords разбивает реплики на нормализованные слова; время слова
+// оценивается пропорционально его положению в тексте реплики
+func splitCueWords(cues []SubtitleCue) []cueWord {
+	var words []cueWord
+	for i, c := range cues {
+		fields := strings.Fields(normalizeCueText(c.Text))
+		total := 0
+		for _, f := range fields {
+			total += utf8.RuneCountInString(f) + 1
+		}
+		pos := 0
+		for _, f := range fields {
+			at := c.Start
+			if total > 0 {
+				at += (c.End - c.Start) * int64(pos) / int64(total)
+			}
+			words = append(words, cueWord{cue: i, norm: f, at: at})
+			pos += utf8.RuneCountInString(f) + 1
+		}
+	}
+	return words
+}
+
+// recognizedWords собирает пословные метки распознанного документа
+func recognizedWords(doc *SubtitleDocument) []Word {
+	var words []Word
+	for _, c := range doc.Cues {
+		for _, w := range c.Words {
+			if normalizeCueText(w.Text) != "" {
+				words = append(words, w)
+			}
+		}
+	}
+	return words
+}
+
+// normalizedTexts нормализует слова для alignWords. Слово из нескольких
+// частей после нормализации («кто-то» → «ктото») остаётся одним
+func normalizedTexts(words []Word) []string {
+	result := make([]string, len(words))
+	for i, w := range words {
+		result[i] = strings.ReplaceAll(normalizeCueText(w.Text), " ", "")
+	}
+	return result
+}
+
+// SyncSubtitles синхронизирует готовые субтитры srtPath с аудио videoPath:
+// аудио распознаётся небольшой моделью, слова субтитров выравниваются с
+// распознанными, и каждая реплика сдвигается на медианное расхождение своих
+// слов. Текст реплик не меняется. srtPath может быть в любом формате
+// ImportSubtitles; результат пишется рядом как *.synced.srt.
+func (a *App) SyncSubtitles(videoPath string, srtPath string, lang string) (*SyncReport, error) {
+	log.Printf("[SyncSubtitles] Синхронизация: видео=%s, субтитры=%s\n", videoPath, srtPath)
+	// Кодировку и формат (SRT, VTT, ASS...) определяет импорт
+	imported, err := a.ImportSubtitles(srtPath)
+	if err != nil {
+		log.Printf("[SyncSubtitles] Ошибка чтения субтитров: %v\n", err)
+		return nil, err
+	}
+	cues := imported.Document.Cues
+	model, err := a.pickLocalModel(syncModelPreference)
+	if err != nil {
+		return nil, err
+	}
+	recognized, err := a.Transcribe(videoPath, TranscribeOptions{Lang: lang, Model: model, WordTimestamps: true})
+	if err != nil {
+		return nil, err
+	}
+
+	ref := splitCueWords(cues)
+	hyp := recognizedWords(recognized)
+	refNorm := make([]string, len(ref))
+	for i, w := range ref {
+		refNorm[i] = w.norm
+	}
+	match := alignWords(refNorm, normalizedTexts(hyp))
+
+	deltas := make([][]int64, len(cues))
+	for i, j := range match {
+		if j >= 0 {
+			deltas[ref[i].cue] = append(deltas[ref[i].cue], hyp[j].Start-ref[i].at)
+		}
+	}
+	report := &SyncReport{Model: model, Cues: make([]CueSync, len(cues))}
+	offsets := make([]int64, len(cues))
+	known := make([]bool, len(cues))
+	for i, c := range cues {
+		report.Cues[i] = CueSync{CueIndex: c.Index, Matched: len(deltas[i]), Words: len(strings.Fields(normalizeCueText(c.Text)))}
+		// Одно совпавшее слово в длинной реплике может быть случайным
+		if len(deltas[i]) > 0 && (len(deltas[i]) >= 2 || report.Cues[i].Words <= 2) {
+			offsets[i] = median(deltas[i])
+			known[i] = true
+			report.Cues[i].Method = "matched"
+		}
+	}
+	interpolateOffsets(cues, offsets, known)
+
+	synced := make([]SubtitleCue, len(cues))
+	for i, c := range cues {
+		if !known[i] {
+			report.Cues[i].Method = "interpolated"
+		}
+		report.Cues[i].Offset = offsets[i]
+		c.Start = max(c.Start+offsets[i], 0)
+		c.End = max(c.End+offsets[i], c.Start)
+		synced[i] = c
+	}
+	// После сдвига соседние реплики не должны налезать друг на друга
+	for i := 0; i+1 < len(synced); i++ {
+		if synced[i].End > synced[i+1].Start {
+			synced[i].End = max(synced[i+1].Start, synced[i].Start)
+		}
+	}
+
+	report.Document = &SubtitleDocument{Language: recognized.Language, Cues: synced}
+	outPath := strings.TrimSuffix(srtPath, filepath.Ext(srtPath)) + ".synced.srt"
+	report.OutputPath, err = writeWithCollisionPolicy(outPath, []byte(formatSRT(synced)), collisionRename)
+	if err != nil {
+		log.Printf("[SyncSubtitles] Ошибка записи: %v\n", err)
+		return nil, err
+	}
+	log.Printf("[SyncSubtitles] Готово: %s\n", report.OutputPath)
+	return report, nil
+}
+
+func median(values []int64) int64 {
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// interpolateOffsets заполняет сдвиги реплик без совпадений: между двумя
+// известными — линейно по времени, по краям — ближайшим известным
+func interpolateOffsets(cues []SubtitleCue, offsets []int64, known []bool) {
+	prev := -1
+	for i := 0; i <= len(cues); i++ {
+		if i < len(cues) && !known[i] {
+			continue
+		}
+		for k := prev + 1; k < i; k++ {
+			switch {
+			case prev < 0 && i == len(cues):
+				offsets[k] = 0
+			case prev < 0:
+				offsets[k] = offsets[i]
+			case i == len(cues):
+				offsets[k] = offsets[prev]
+			default:
+				span := cues[i].Start - cues[prev].Start
+				if span <= 0 {
+					offsets[k] = offsets[prev]
+					continue
+				}
+				frac := float64(cues[k].Start-cues[prev].Start) / float64(span)
+				offsets[k] = offsets[prev] + int64(frac*float64(offsets[i]-offsets[prev]))
+			}
+		}
+		prev = i
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+var (
+	vmStatLine     = regexp.MustCompile(`^Pages (free|inactive|speculative):\s+(\d+)`)
+	vmStatPageSize = regexp.MustCompile(`page size of (\d+) bytes`)
+)
+
+// availableMemory возвращает объём памяти, доступной без вытеснения, в
+// байтах: свободные и неактивные страницы по vm_stat
+func availableMemory() int64 {
+	out, err := exec.Command("vm_stat").Output()
+	if err != nil {
+		return 0
+	}
+	pageSize := int64(4096)
+	if m := vmStatPageSize.FindSubmatch(out); m != nil {
+		pageSize, _ = strconv.ParseInt(string(m[1]), 10, 64)
+	}
+	var pages int64
+	for _, line := range strings.Split(string(out), "\n") {
+		if m := vmStatLine.FindStringSubmatch(line); m != nil {
+			n, _ := strconv.ParseInt(m[2], 10, 64)
+			pages += n
+		}
+	}
+	return pages * pageSize
+}
+
+// freeDiskSpace возвращает свободное место на диске с каталогом dir в байтах
+func freeDiskSpace(dir string) int64 {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return 0
+	}
+	return int64(st.Bavail) * int64(st.Bsize)
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !windows
+
+package main
+
+// availableMemory на этой платформе не определяется; 0 — неизвестно
+func availableMemory() int64 { return 0 }
+
+// freeDiskSpace на этой платформе не определяется; 0 — неизвестно
+func freeDiskSpace(dir string) int64 { return 0 }
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32               = syscall.NewLazyDLL("kernel32.dll")
+	procGlobalMemoryStatus = kernel32.NewProc("GlobalMemoryStatusEx")
+	procGetDiskFreeSpace   = kernel32.NewProc("GetDiskFreeSpaceExW")
+)
+
+// memoryStatusEx структура MEMORYSTATUSEX из WinAPI
+type memoryStatusEx struct {
+	Length               uint32
+	MemoryLoad           uint32
+	TotalPhys            uint64
+	AvailPhys            uint64
+	TotalPageFile        uint64
+	AvailPageFile        uint64
+	TotalVirtual         uint64
+	AvailVirtual         uint64
+	AvailExtendedVirtual uint64
+}
+
+// availableMemory возвращает объём доступной физической памяти в байтах
+func availableMemory() int64 {
+	st := memoryStatusEx{}
+	st.Length = uint32(unsafe.Sizeof(st))
+	if r, _, _ := procGlobalMemoryStatus.Call(uintptr(unsafe.Pointer(&st))); r == 0 {
+		return 0
+	}
+	return int64(st.AvailPhys)
+}
+
+// freeDiskSpace возвращает свободное место на диске с каталогом dir в байтах
+func freeDiskSpace(dir string) int64 {
+	path, err := syscall.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0
+	}
+	var free uint64
+	if r, _, _ := procGetDiskFreeSpace.Call(uintptr(unsafe.Pointer(path)), uintptr(unsafe.Pointer(&free)), 0, 0); r == 0 {
+		return 0
+	}
+	return int64(free)
+}
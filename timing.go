@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Порог scene-детектора ffmpeg, выше которого кадр считается сменой плана
+	shotChangeThreshold = 0.4
+	// Граница реплики притягивается к смене плана, если она ближе этого (мс)
+	defaultShotSnapDistance = 250
+)
+
+// TimingChange изменение времени одной реплики
+type TimingChange struct {
+	CueIndex int   `json:"cueIndex"`
+	OldStart int64 `json:"oldStart"`
+	OldEnd   int64 `json:"oldEnd"`
+	NewStart int64 `json:"newStart"`
+	NewEnd   int64 `json:"newEnd"`
+}
+
+// TimingPreview результат операции со временем: новый документ и список
+// изменений, чтобы показать их пользователю до сохранения
+type TimingPreview struct {
+	Document *SubtitleDocument `json:"document"`
+	Changes  []TimingChange    `json:"changes"`
+}
+
+// SyncPoint пара «время в субтитрах → правильное время» в миллисекундах
+type SyncPoint struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+// retimeDocument применяет fn ко всем меткам времени реплик, для которых
+// selected возвращает true, не изменяя исходный документ. Реплика, у которой
+// начало и конец попали в одну точку, получает длительность minDuration
+// (0 — сохраняет прежнюю).
+func retimeDocument(doc SubtitleDocument, selected func(SubtitleCue) bool, fn func(int64) int64, minDuration int64) *TimingPreview {
+	preview := &TimingPreview{}
+	cues := make([]SubtitleCue, len(doc.Cues))
+	for i, c := range doc.Cues {
+		if selected(c) {
+			old := c
+			c.Start = max(fn(c.Start), 0)
+			c.End = max(fn(c.End), c.Start)
+			if c.End == c.Start && old.End > old.Start {
+				if minDuration <= 0 {
+					c.End = c.Start + old.End - old.Start
+				} else {
+					c.End = c.Start + minDuration
+				}
+			}
+			if len(c.Words) > 0 {
+				words := make([]Word, len(c.Words))
+				for j, w := range c.Words {
+					w.Start = max(fn(w.Start), 0)
+					w.End = max(fn(w.End), w.Start)
+					words[j] = w
+				}
+				c.Words = words
+			}
+			if c.Start != old.Start || c.End != old.End {
+				preview.Changes = append(preview.Changes, TimingChange{
+					CueIndex: c.Index, OldStart: old.Start, OldEnd: old.End, NewStart: c.Start, NewEnd: c.End,
+				})
+			}
+		}
+		cues[i] = c
+	}
+	doc.Cues = cues
+	preview.Document = &doc
+	return preview
+}
+
+// indexRange выбирает реплики с номерами from..to включительно; 0 снимает ограничение
+func indexRange(from, to int) func(SubtitleCue) bool {
+	return func(c SubtitleCue) bool {
+		return (from <= 0 || c.Index >= from) && (to <= 0 || c.Index <= to)
+	}
+}
+
+// ShiftSubtitles сдвигает реплики с номерами from..to (0 — без ограничения)
+// на offset миллисекунд
+func (a *App) ShiftSubtitles(doc SubtitleDocument, offset int64, from int, to int) *TimingPreview {
+	log.Printf("[ShiftSubtitles] Сдвиг на %d мс, реплики %d-%d\n", offset, from, to)
+	return retimeDocument(doc, indexRange(from, to), func(t int64) int64 { return t + offset }, 1)
+}
+
+// StretchSubtitles линейно растягивает время так, чтобы обе точки синхронизации
+// совпали; исправляет постепенное расхождение (дрейф) субтитров
+func (a *App) StretchSubtitles(doc SubtitleDocument, p1 SyncPoint, p2 SyncPoint) (*TimingPreview, error) {
+	log.Printf("[StretchSubtitles] Синхронизация по точкам %+v и %+v\n", p1, p2)
+	if p1.From == p2.From {
+		return nil, errors.New("sync points must differ")
+	}
+	scale := float64(p2.To-p1.To) / float64(p2.From-p1.From)
+	if scale <= 0 {
+		return nil, errors.New("sync points reverse the timeline")
+	}
+	return retimeDocument(doc, indexRange(0, 0), func(t int64) int64 {
+		return p1.To + int64(math.Round(float64(t-p1.From)*scale))
+	}, 1), nil
+}
+
+// ConvertFramerate пересчитывает время субтитров, сделанных под видео с частотой
+// fromFps, для того же видео, воспроизводимого с частотой toFps (например,
+// 23.976 → 25 при PAL-ускорении)
+func (a *App) ConvertFramerate(doc SubtitleDocument, fromFps float64, toFps float64) (*TimingPreview, error) {
+	log.Printf("[ConvertFramerate] %.3f -> %.3f fps\n", fromFps, toFps)
+	if fromFps <= 0 || toFps <= 0 {
+		return nil, errors.New("framerate must be positive")
+	}
+	scale := fromFps / toFps
+	return retimeDocument(doc, indexRange(0, 0), func(t int64) int64 {
+		return int64(math.Round(float64(t) * scale))
+	}, 1), nil
+}
+
+// SnapToFrames выравнивает начало и конец реплик по ближайшей границе кадра.
+// Короткая реплика, оба края которой попали на один кадр, длится один кадр.
+func (a *App) SnapToFrames(doc SubtitleDocument, fps float64) (*TimingPreview, error) {
+	log.Printf("[SnapToFrames] Выравнивание по кадрам %.3f fps\n", fps)
+	if fps <= 0 {
+		return nil, errors.New("framerate must be positive")
+	}
+	frame := 1000 / fps
+	return retimeDocument(doc, indexRange(0, 0), func(t int64) int64 {
+		return int64(math.Round(math.Round(float64(t)/frame) * frame))
+	}, int64(math.Ceil(frame))), nil
+}
+
+// SnapToShotChanges притягивает границы реплик к сменам плана в видео,
+// если они ближе maxDistance миллисекунд (0 — значение по умолчанию).
+// Если оба края реплики притянулись к одной смене плана, длительность
+// реплики сохраняется.
+func (a *App) SnapToShotChanges(filePath string, doc SubtitleDocument, maxDistance int64) (*TimingPreview, error) {
+	log.Printf("[SnapToShotChanges] Поиск смен плана: файл=%s\n", filePath)
+	if maxDistance <= 0 {
+		maxDistance = defaultShotSnapDistance
+	}
+	shots, err := detectShotChanges(filePath, shotChangeThreshold)
+	if err != nil {
+		log.Printf("[SnapToShotChanges] Ошибка: %v\n", err)
+		return nil, err
+	}
+	log.Printf("[SnapToShotChanges] Найдено смен плана: %d\n", len(shots))
+	return retimeDocument(doc, indexRange(0, 0), func(t int64) int64 {
+		i := sort.Search(len(shots), func(i int) bool { return shots[i] >= t })
+		best := t
+		bestDist := maxDistance + 1
+		for _, j := range []int{i - 1, i} {
+			if j >= 0 && j < len(shots) {
+				if d := abs64(shots[j] - t); d < bestDist {
+					best, bestDist = shots[j], d
+				}
+			}
+		}
+		return best
+	}, 0), nil
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// detectShotChanges возвращает отсортированные моменты смены плана (мс)
+// по фильтру scene ffmpeg
+func detectShotChanges(filePath string, threshold float64) ([]int64, error) {
+	filter := fmt.Sprintf("select='gt(scene,%.2f)',showinfo", threshold)
+	cmd := exec.Command(ffmpegPath, "-hide_banner", "-nostats", "-i", filePath, "-an", "-vf", filter, "-f", "null", "-")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg error: %v, out: %s", err, string(out))
+	}
+	var shots []int64
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		i := strings.Index(line, "pts_time:")
+		if !strings.Contains(line, "Parsed_showinfo") || i < 0 {
+			continue
+		}
+		fields := strings.Fields(line[i+len("pts_time:"):])
+		if len(fields) == 0 {
+			continue
+		}
+		if v, err := strconv.ParseFloat(fields[0], 64); err == nil {
+			shots = append(shots, int64(math.Round(v*1000)))
+		}
+	}
+	sort.Slice(shots, func(i, j int) bool { return shots[i] < shots[j] })
+	return shots, nil
+}
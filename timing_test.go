@@ -0,0 +1,132 @@
+package main
+
+import "testing"
+
+func timingDoc() SubtitleDocument {
+	return SubtitleDocument{Cues: []SubtitleCue{
+		{Index: 1, Start: 1000, End: 2000, Text: "один", Words: []Word{{Text: "один", Start: 1000, End: 2000}}},
+		{Index: 2, Start: 3000, End: 4000, Text: "два"},
+		{Index: 3, Start: 5000, End: 6000, Text: "три"},
+	}}
+}
+
+func cueTimes(doc *SubtitleDocument) [][2]int64 {
+	times := make([][2]int64, len(doc.Cues))
+	for i, c := range doc.Cues {
+		times[i] = [2]int64{c.Start, c.End}
+	}
+	return times
+}
+
+func checkTimes(t *testing.T, name string, got, want [][2]int64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s: %d cues, want %d", name, len(got), len(want))
+		return
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("%s: cue %d = %v, want %v", name, i, got[i], want[i])
+		}
+	}
+}
+
+func TestShiftSubtitles(t *testing.T) {
+	a := NewApp()
+	tests := []struct {
+		name     string
+		offset   int64
+		from, to int
+		want     [][2]int64
+		changes  int
+	}{
+		{"all", 500, 0, 0, [][2]int64{{1500, 2500}, {3500, 4500}, {5500, 6500}}, 3},
+		{"range", -500, 2, 3, [][2]int64{{1000, 2000}, {2500, 3500}, {4500, 5500}}, 2},
+		{"from only", 100, 3, 0, [][2]int64{{1000, 2000}, {3000, 4000}, {5100, 6100}}, 1},
+		// Реплики до начала видео не схлопываются в ноль
+		{"clamped", -5000, 0, 0, [][2]int64{{0, 1}, {0, 1}, {0, 1000}}, 3},
+	}
+	for _, tt := range tests {
+		doc := timingDoc()
+		p := a.ShiftSubtitles(doc, tt.offset, tt.from, tt.to)
+		checkTimes(t, tt.name, cueTimes(p.Document), tt.want)
+		if len(p.Changes) != tt.changes {
+			t.Errorf("%s: %d changes, want %d", tt.name, len(p.Changes), tt.changes)
+		}
+		if doc.Cues[0].Start != 1000 || doc.Cues[0].Words[0].Start != 1000 {
+			t.Errorf("%s: source document modified", tt.name)
+		}
+	}
+
+	p := a.ShiftSubtitles(timingDoc(), 250, 0, 0)
+	if w := p.Document.Cues[0].Words[0]; w.Start != 1250 || w.End != 2250 {
+		t.Errorf("word timings not shifted: %+v", w)
+	}
+}
+
+func TestStretchSubtitles(t *testing.T) {
+	a := NewApp()
+	p, err := a.StretchSubtitles(timingDoc(), SyncPoint{From: 1000, To: 1000}, SyncPoint{From: 5000, To: 9000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTimes(t, "stretch", cueTimes(p.Document), [][2]int64{{1000, 3000}, {5000, 7000}, {9000, 11000}})
+
+	if _, err := a.StretchSubtitles(timingDoc(), SyncPoint{From: 1000, To: 0}, SyncPoint{From: 1000, To: 500}); err == nil {
+		t.Errorf("equal sync points accepted")
+	}
+	if _, err := a.StretchSubtitles(timingDoc(), SyncPoint{From: 1000, To: 5000}, SyncPoint{From: 2000, To: 1000}); err == nil {
+		t.Errorf("reversing sync points accepted")
+	}
+}
+
+func TestConvertFramerate(t *testing.T) {
+	a := NewApp()
+	p, err := a.ConvertFramerate(timingDoc(), 25, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTimes(t, "framerate", cueTimes(p.Document), [][2]int64{{500, 1000}, {1500, 2000}, {2500, 3000}})
+	if _, err := a.ConvertFramerate(timingDoc(), 0, 25); err == nil {
+		t.Errorf("zero framerate accepted")
+	}
+}
+
+func TestSnapToFrames(t *testing.T) {
+	a := NewApp()
+	doc := SubtitleDocument{Cues: []SubtitleCue{
+		{Index: 1, Start: 1010, End: 1990, Text: "a"},
+		// Оба края ближе всего к кадру 1040 мс
+		{Index: 2, Start: 1030, End: 1050, Text: "b"},
+		{Index: 3, Start: 2000, End: 2000, Text: "c"},
+	}}
+	p, err := a.SnapToFrames(doc, 25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTimes(t, "frames", cueTimes(p.Document), [][2]int64{{1000, 2000}, {1040, 1080}, {2000, 2000}})
+	if _, err := a.SnapToFrames(doc, -1); err == nil {
+		t.Errorf("negative framerate accepted")
+	}
+}
+
+func TestRetimeDocumentKeepsDuration(t *testing.T) {
+	// Как у SnapToShotChanges: оба края притягиваются к одной точке
+	snap := func(t int64) int64 {
+		if t >= 2900 && t <= 3300 {
+			return 3100
+		}
+		return t
+	}
+	doc := SubtitleDocument{Cues: []SubtitleCue{
+		{Index: 1, Start: 1000, End: 2000},
+		{Index: 2, Start: 3000, End: 3200},
+	}}
+	p := retimeDocument(doc, indexRange(0, 0), snap, 0)
+	checkTimes(t, "keep duration", cueTimes(p.Document), [][2]int64{{1000, 2000}, {3100, 3300}})
+	for _, c := range p.Document.Cues {
+		if c.End <= c.Start {
+			t.Errorf("cue %d collapsed: %d-%d", c.Index, c.Start, c.End)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"strings"
+)
+
+// diarizationModelSuffix отличает модели tinydiarize в whisperModels
+const diarizationModelSuffix = "-tdrz"
+
+// Transcribe транскрибирует весь файл и возвращает документ субтитров.
+// В отличие от GenerateSubtitles, результат структурирован и может содержать
+// спикеров (opts.Diarize). Запуск записывается в журнал с исходными опциями.
+func (a *App) Transcribe(filePath string, opts TranscribeOptions) (_ *SubtitleDocument, err error) {
+	log.Printf("[Transcribe] Транскрибация: файл=%s, опции=%+v\n", filePath, opts)
+	run := a.startHistory("transcribe", filePath, opts)
+	defer func() { a.finishHistory(run, err) }()
+	if err := normalizeTranscribeOptions(&opts); err != nil {
+		log.Printf("[Transcribe] Некорректные опции: %v\n", err)
+		return nil, err
+	}
+	modelPath, err := a.resolveModelPath(opts.Model)
+	if err != nil {
+		log.Printf("[Transcribe] %v\n", err)
+		return nil, err
+	}
+
+	out, err := a.runWhisper(filePath, modelPath, 0, 0, opts)
+	if err != nil {
+		log.Printf("[Transcribe] Ошибка whisper-cli: %v\n", err)
+		return nil, err
+	}
+
+	lang := opts.Lang
+	if opts.Translate {
+		lang = "en"
+	}
+	doc := &SubtitleDocument{
+		Language: lang,
+		Model:    opts.Model,
+		Cues:     out.cues(opts.Diarize, opts.WordTimestamps),
+	}
+	if opts.Diarize {
+		doc.Speakers = defaultSpeakerNames(doc.Cues)
+	}
+	if opts.HallucinationFilter != "" {
+		report, err := a.FilterHallucinations(filePath, *doc, HallucinationFilterOptions{Mode: opts.HallucinationFilter, CheckSilence: true})
+		if err != nil {
+			log.Printf("[Transcribe] Ошибка фильтра галлюцинаций: %v\n", err)
+			return nil, err
+		}
+		for _, d := range report.Dropped {
+			log.Printf("[Transcribe] Галлюцинация (%s) %s: %q\n", d.Reason, formatSRTTimestamp(d.Cue.Start), d.Cue.Text)
+		}
+		doc = report.Document
+	}
+	log.Printf("[Transcribe] Готово: %d реплик\n", len(doc.Cues))
+	run.Cues = len(doc.Cues)
+	return doc, nil
+}
+
+// normalizeTranscribeOptions подставляет значения по умолчанию и проверяет
+// совместимость опций с моделью
+func normalizeTranscribeOptions(opts *TranscribeOptions) error {
+	if opts.Translate {
+		if opts.Diarize {
+			return errors.New("Перевод и разделение по спикерам нельзя включить одновременно")
+		}
+		// Английская модель на --translate молча возвращает транскрибацию
+		if englishOnlyModel(opts.Model) {
+			return errors.New("Модель " + opts.Model + " знает только английский и не умеет переводить")
+		}
+		// Для перевода исходный язык определяется автоматически
+		if opts.Lang == "" {
+			opts.Lang = "auto"
+		}
+	}
+	if opts.Diarize {
+		if !strings.HasSuffix(opts.Model, diarizationModelSuffix) {
+			return errors.New("Для разделения по спикерам нужна модель small.en-tdrz")
+		}
+		// tinydiarize обучена только на английском
+		if opts.Lang == "" {
+			opts.Lang = "en"
+		}
+		if opts.Lang != "en" {
+			return errors.New("Разделение по спикерам поддерживается только для английского языка")
+		}
+	}
+	if opts.Lang == "" {
+		opts.Lang = "ru"
+	}
+	return nil
+}
+
+// defaultSpeakerNames даёт спикерам, встречающимся в репликах, имена по умолчанию
+func defaultSpeakerNames(cues []SubtitleCue) map[string]string {
+	names := map[string]string{}
+	for _, c := range cues {
+		if c.Speaker != "" {
+			if _, ok := names[c.Speaker]; !ok {
+				names[c.Speaker] = "Speaker " + strings.TrimPrefix(c.Speaker, "S")
+			}
+		}
+	}
+	return names
+}
+
+// RenameSpeaker задаёт отображаемое имя спикера и возвращает обновлённый документ
+func (a *App) RenameSpeaker(doc SubtitleDocument, speakerID string, name string) (*SubtitleDocument, error) {
+	log.Printf("[RenameSpeaker] %s -> %s\n", speakerID, name)
+	if _, ok := doc.Speakers[speakerID]; !ok {
+		return nil, errors.New("unknown speaker")
+	}
+	speakers := make(map[string]string, len(doc.Speakers))
+	for id, n := range doc.Speakers {
+		speakers[id] = n
+	}
+	speakers[speakerID] = strings.TrimSpace(name)
+	doc.Speakers = speakers
+	return &doc, nil
+}
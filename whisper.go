@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// TranscribeOptions параметры запуска whisper-cli
+type TranscribeOptions struct {
+	Lang  string `json:"lang"`
+	Model string `json:"model"`
+	// Diarize включает разметку смены спикера (-tdrz), нужна модель *-tdrz
+	Diarize bool `json:"diarize"`
+	// Translate переводит речь на английский (--translate) вместо транскрибации
+	Translate bool `json:"translate"`
+	// WordTimestamps сохраняет в репликах пословные метки времени
+	WordTimestamps bool `json:"wordTimestamps"`
+	// HallucinationFilter: "" — выключен, remove или flag (см. FilterHallucinations)
+	HallucinationFilter string `json:"hallucinationFilter"`
+	// ForceRefresh распознаёт заново, даже если результат есть в кэше
+	ForceRefresh bool `json:"forceRefresh,omitempty"`
+	// noCache не обращается к кэшу вовсе: не считает ключ и не сохраняет
+	// результат. Нужен замерам (benchmark), которые не должны вытеснять
+	// записи пользователя.
+	noCache bool
+}
+
+// whisperOutput соответствует JSON, который whisper-cli пишет с флагом -ojf
+type whisperOutput struct {
+	Result struct {
+		Language string `json:"language"`
+	} `json:"result"`
+	Transcription []whisperSegment `json:"transcription"`
+	// peakMemory пиковый объём памяти процесса whisper-cli в байтах, 0 — неизвестен
+	// (в том числе для результата из кэша)
+	peakMemory int64
+	// elapsed время работы самого whisper-cli, 0 — результат взят из кэша
+	elapsed time.Duration
+}
+
+type whisperSegment struct {
+	Offsets struct {
+		From int64 `json:"from"`
+		To   int64 `json:"to"`
+	} `json:"offsets"`
+	Text string `json:"text"`
+	// SpeakerTurnNext выставляется tinydiarize, если после сегмента говорит другой спикер
+	SpeakerTurnNext bool `json:"speaker_turn_next"`
+	// Tokens заполняется только в полном JSON (-ojf)
+	Tokens []whisperToken `json:"tokens"`
+}
+
+type whisperToken struct {
+	// Текст токена оставлен сырым: whisper режет UTF-8 по байтам, и кириллическая
+	// буква может оказаться разделена между двумя токенами
+	Text    json.RawMessage `json:"text"`
+	Offsets struct {
+		From int64 `json:"from"`
+		To   int64 `json:"to"`
+	} `json:"offsets"`
+	P float64 `json:"p"`
+}
+
+// decodeJSONStringBytes раскодирует JSON-строку в байты без замены
+// некорректных UTF-8 последовательностей на U+FFFD, как это делает encoding/json
+func decodeJSONStringBytes(raw []byte) []byte {
+	if len(raw) < 2 || raw[0] != '"' || raw[len(raw)-1] != '"' {
+		return nil
+	}
+	raw = raw[1 : len(raw)-1]
+	out := make([]byte, 0, len(raw))
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		if c != '\\' || i+1 >= len(raw) {
+			out = append(out, c)
+			continue
+		}
+		i++
+		switch raw[i] {
+		case 'n':
+			out = append(out, '\n')
+		case 't':
+			out = append(out, '\t')
+		case 'r':
+			out = append(out, '\r')
+		case 'b':
+			out = append(out, '\b')
+		case 'f':
+			out = append(out, '\f')
+		case 'u':
+			if i+4 < len(raw) {
+				if v, err := strconv.ParseUint(string(raw[i+1:i+5]), 16, 32); err == nil {
+					out = utf8.AppendRune(out, rune(v))
+					i += 4
+					continue
+				}
+			}
+			out = append(out, '\\', 'u')
+		default:
+			out = append(out, raw[i])
+		}
+	}
+	return out
+}
+
+// confidence возвращает среднюю вероятность значимых токенов сегмента
+func (seg *whisperSegment) confidence() float64 {
+	var sum float64
+	var n int
+	for _, tok := range seg.Tokens {
+		if b := decodeJSONStringBytes(tok.Text); len(b) > 0 && !isSpecialToken(b) {
+			sum += tok.P
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// isSpecialToken отсекает служебные токены вида [_BEG_] и [_TT_150]
+func isSpecialToken(text []byte) bool {
+	return bytes.HasPrefix(text, []byte("[_")) && bytes.HasSuffix(text, []byte("]"))
+}
+
+// words собирает токены сегмента в слова: новое слово начинается с токена,
+// открывающегося пробелом. Уверенность слова — средняя вероятность его токенов.
+func (seg *whisperSegment) words() []Word {
+	var words []Word
+	var text []byte
+	var cur Word
+	var pSum float64
+	var n int
+	flush := func() {
+		t := strings.TrimSpace(strings.ToValidUTF8(string(text), ""))
+		if t != "" && n > 0 {
+			cur.Text = t
+			cur.Confidence = pSum / float64(n)
+			words = append(words, cur)
+		}
+		text, pSum, n = nil, 0, 0
+	}
+	for _, tok := range seg.Tokens {
+		b := decodeJSONStringBytes(tok.Text)
+		if len(b) == 0 || isSpecialToken(b) {
+			continue
+		}
+		if n == 0 || b[0] == ' ' {
+			flush()
+			cur = Word{Start: tok.Offsets.From}
+		}
+		text = append(text, b...)
+		cur.End = tok.Offsets.To
+		pSum += tok.P
+		n++
+	}
+	flush()
+	return words
+}
+
+// whisperArgs собирает аргументы whisper-cli для входного WAV и префикса вывода
+func whisperArgs(modelPath, wavPath, outPrefix string, opts TranscribeOptions) []string {
+	// Полный JSON нужен всегда: из вероятностей токенов считается уверенность реплик
+	args := []string{"-m", modelPath, "-f", wavPath, "-ojf", "-of", outPrefix, "-l", opts.Lang}
+	if opts.Diarize {
+		args = append(args, "-tdrz")
+	}
+	if opts.Translate {
+		args = append(args, "-tr")
+	}
+	return args
+}
+
+// runWhisper вырезает кусок [start, end) файла в WAV, запускает whisper-cli
+// и разбирает его JSON-вывод. Время сегментов — относительно начала куска.
+// Вывод для того же звука, модели и опций берётся из кэша (см. cache.go),
+// если не задан opts.ForceRefresh; при opts.noCache кэш не используется.
+func (a *App) runWhisper(filePath, modelPath string, start, end float64, opts TranscribeOptions) (*whisperOutput, error) {
+	tmpDir, err := os.MkdirTemp("", "submagic_whisper_")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	wavPath := filepath.Join(tmpDir, "audio.wav")
+	if err := extractAudioSegment(filePath, wavPath, start, end); err != nil {
+		return nil, err
+	}
+
+	cache := a.cacheSettings()
+	var key string
+	if !cache.Disabled && !opts.noCache {
+		if key, err = whisperCacheKey(wavPath, modelPath, opts); err != nil {
+			log.Printf("[runWhisper] Ошибка вычисления ключа кэша: %v\n", err)
+		}
+	}
+	if key != "" && !opts.ForceRefresh {
+		if data := a.cacheLookup(key); data != nil {
+			var result whisperOutput
+			if err := json.Unmarshal(data, &result); err == nil {
+				log.Printf("[runWhisper] Результат взят из кэша: %s\n", key[:12])
+				return &result, nil
+			}
+		}
+	}
+
+	outPrefix := filepath.Join(tmpDir, "audio")
+	cmd := exec.Command(whisperPath, whisperArgs(modelPath, wavPath, outPrefix, opts)...)
+	started := time.Now()
+	out, peak, err := runMeasured(cmd)
+	elapsed := time.Since(started)
+	if err != nil {
+		return nil, fmt.Errorf("whisper-cli error: %v, out: %s", err, string(out))
+	}
+	data, err := os.ReadFile(outPrefix + ".json")
+	if err != nil {
+		return nil, err
+	}
+	var result whisperOutput
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("whisper-cli: invalid JSON output: %w", err)
+	}
+	result.peakMemory = peak
+	result.elapsed = elapsed
+	if key != "" {
+		a.cacheStore(key, data, cache.MaxBytes)
+	}
+	return &result, nil
+}
+
+// cues преобразует сегменты whisper в реплики. При диаризации спикеры
+// чередуются S1/S2 на каждой отметке смены: tinydiarize сообщает только
+// о смене говорящего, но не о том, кто именно говорит.
+// Пословные метки сохраняются только при withWords.
+func (w *whisperOutput) cues(diarize, withWords bool) []SubtitleCue {
+	var cues []SubtitleCue
+	speaker := 1
+	for _, seg := range w.Transcription {
+		text := strings.TrimSpace(seg.Text)
+		if text != "" {
+			c := SubtitleCue{
+				Index:      len(cues) + 1,
+				Start:      seg.Offsets.From,
+				End:        seg.Offsets.To,
+				Text:       text,
+				Confidence: seg.confidence(),
+			}
+			if withWords {
+				c.Words = seg.words()
+			}
+			if diarize {
+				c.Speaker = fmt.Sprintf("S%d", speaker)
+			}
+			cues = append(cues, c)
+		}
+		if diarize && seg.SpeakerTurnNext {
+			speaker = 3 - speaker
+		}
+	}
+	return cues
+}